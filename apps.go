@@ -0,0 +1,377 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// AppsService handles communications with the v2 apps API of OneLogin.
+type AppsService service
+
+// App represents an app connected to OneLogin.
+type App struct {
+	ID           int64                    `json:"id"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description"`
+	Notes        string                   `json:"notes"`
+	ConnectorID  int64                    `json:"connector_id"`
+	PolicyID     int64                    `json:"policy_id"`
+	BrandID      int64                    `json:"brand_id"`
+	IconURL      string                   `json:"icon_url"`
+	Visible      bool                     `json:"visible"`
+	AuthMethod   int                      `json:"auth_method"`
+	TabID        int64                    `json:"tab_id"`
+	RoleIDs      []int64                  `json:"role_ids"`
+	CreatedAt    Time                     `json:"created_at"`
+	UpdatedAt    Time                     `json:"updated_at"`
+	Parameters   map[string]*AppParameter `json:"parameters"`
+	Provisioning *AppProvisioning         `json:"provisioning"`
+
+	// Configuration and SSO depend on the connector of the app.
+	Configuration map[string]interface{} `json:"configuration"`
+	SSO           map[string]interface{} `json:"sso"`
+}
+
+// AppParameter is a parameter of an app, usually mapping a user attribute
+// into the SAML assertion or the provisioned account.
+// All its fields but the ID are sent when saved, so the zero values clear them.
+type AppParameter struct {
+	ID                        int64  `json:"id,omitempty"`
+	Label                     string `json:"label"`
+	UserAttributeMappings     string `json:"user_attribute_mappings"`
+	UserAttributeMacros       string `json:"user_attribute_macros"`
+	AttributesTransformations string `json:"attributes_transformations"`
+	DefaultValues             string `json:"default_values"`
+	Values                    string `json:"values"`
+	SkipIfBlank               bool   `json:"skip_if_blank"`
+	ProvisionedEntitlements   bool   `json:"provisioned_entitlements"`
+	IncludeInSAMLAssertion    bool   `json:"include_in_saml_assertion"`
+}
+
+// AppProvisioning is the provisioning configuration of an app.
+type AppProvisioning struct {
+	Enabled bool `json:"enabled"`
+}
+
+// AppListOptions filters the apps returned by AppsService.List.
+type AppListOptions struct {
+	ListOptions
+
+	Name        string `url:"name,omitempty"`
+	ConnectorID int64  `url:"connector_id,omitempty"`
+}
+
+// List returns all the apps matching opts.
+// The apps returned by List don't carry the full parameters, use Get for that.
+func (s *AppsService) List(ctx context.Context, opts *AppListOptions) ([]*App, error) {
+	p := newPager(s.client, "/api/2/apps", opts)
+
+	var apps []*App
+	for {
+		var as []*App
+		ok, err := p.next(ctx, &as)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return apps, nil
+		}
+		apps = append(apps, as...)
+	}
+}
+
+// Get returns an app, including its parameters and provisioning configuration.
+func (s *AppsService) Get(ctx context.Context, id int64) (*App, error) {
+	return s.GetWithOptions(ctx, id, nil)
+}
+
+// GetWithOptions returns an app, configured by opts.
+func (s *AppsService) GetWithOptions(ctx context.Context, id int64, opts *GetOptions) (*App, error) {
+	u, err := addOptions(fmt.Sprintf("/api/2/apps/%v", id), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var app App
+	if _, err := s.client.Do(ctx, req, &app); err != nil {
+		return nil, err
+	}
+
+	return &app, nil
+}
+
+type appParametersParams struct {
+	Name        string                   `json:"name"`
+	ConnectorID int64                    `json:"connector_id"`
+	Parameters  map[string]*AppParameter `json:"parameters"`
+}
+
+// SetParameter creates or replaces the parameter of an app: all the fields of
+// value are sent, so the zero ones clear the current values of the parameter.
+// The parameters are read and written back with the app, so concurrent updates
+// of the parameters of the same app may overwrite each other.
+func (s *AppsService) SetParameter(ctx context.Context, appID int64, key string, value AppParameter) error {
+	app, err := s.Get(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	params := make(map[string]*AppParameter, len(app.Parameters)+1)
+	for k, p := range app.Parameters {
+		params[k] = p
+	}
+	if p, ok := params[key]; ok && value.ID == 0 {
+		// Replace the parameter instead of adding one with the same key.
+		value.ID = p.ID
+	}
+	params[key] = &value
+
+	u := fmt.Sprintf("/api/2/apps/%v", appID)
+
+	req, err := s.client.NewRequest("PUT", u, appParametersParams{
+		Name:        app.Name,
+		ConnectorID: app.ConnectorID,
+		Parameters:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// DeleteParameter deletes the parameter of an app.
+// ErrNotFound is returned when the app has no parameter with the key.
+func (s *AppsService) DeleteParameter(ctx context.Context, appID int64, key string) error {
+	app, err := s.Get(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	p, ok := app.Parameters[key]
+	if !ok || p == nil {
+		return ErrNotFound
+	}
+
+	u := fmt.Sprintf("/api/2/apps/%v/parameters/%v", appID, p.ID)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Connector is a template apps are created from, e.g. a SAML or an OIDC connector.
+type Connector struct {
+	ID                  int64  `json:"id"`
+	Name                string `json:"name"`
+	IconURL             string `json:"icon_url"`
+	AuthMethod          int    `json:"auth_method"`
+	AllowsNewParameters bool   `json:"allows_new_parameters"`
+}
+
+// ConnectorListOptions filters the connectors returned by AppsService.Connectors.
+type ConnectorListOptions struct {
+	ListOptions
+
+	Name       string `url:"name,omitempty"`
+	AuthMethod int    `url:"auth_method,omitempty"`
+}
+
+// Connectors returns all the connectors matching opts, whose ids are needed to create apps.
+func (s *AppsService) Connectors(ctx context.Context, opts *ConnectorListOptions) ([]*Connector, error) {
+	p := newPager(s.client, "/api/2/connectors", opts)
+
+	var connectors []*Connector
+	for {
+		var cs []*Connector
+		ok, err := p.next(ctx, &cs)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return connectors, nil
+		}
+		connectors = append(connectors, cs...)
+	}
+}
+
+// AppRule is a provisioning rule of an app, applying Actions to the users matching
+// its Conditions. The conditions share the format of the mappings ones.
+type AppRule struct {
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Match is either "all" or "any" of the conditions.
+	Match string `json:"match"`
+	// Position is the order in which the rules are applied.
+	Position   *int64              `json:"position,omitempty"`
+	Conditions []*MappingCondition `json:"conditions"`
+	Actions    []*AppRuleAction    `json:"actions"`
+}
+
+// AppRuleAction sets the Value of the Action, or the result of Expression
+// applied to Macro, on the matching users.
+type AppRuleAction struct {
+	Action     string   `json:"action"`
+	Value      []string `json:"value,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+	Macro      string   `json:"macro,omitempty"`
+}
+
+// ListRules returns the rules of an app.
+func (s *AppsService) ListRules(ctx context.Context, appID int64) ([]*AppRule, error) {
+	u := fmt.Sprintf("/api/2/apps/%v/rules", appID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var rules []*AppRule
+	if _, err := s.client.Do(ctx, req, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// GetRule returns a rule of an app.
+func (s *AppsService) GetRule(ctx context.Context, appID, ruleID int64) (*AppRule, error) {
+	u := fmt.Sprintf("/api/2/apps/%v/rules/%v", appID, ruleID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var rule AppRule
+	if _, err := s.client.Do(ctx, req, &rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateRule creates a rule on an app and returns its id.
+func (s *AppsService) CreateRule(ctx context.Context, appID int64, rule *AppRule) (int64, error) {
+	return s.saveRule(ctx, "POST", fmt.Sprintf("/api/2/apps/%v/rules", appID), rule)
+}
+
+// UpdateRule replaces a rule of an app. All its fields are sent, so rule should
+// be the one returned by GetRule, modified.
+func (s *AppsService) UpdateRule(ctx context.Context, appID, ruleID int64, rule *AppRule) error {
+	_, err := s.saveRule(ctx, "PUT", fmt.Sprintf("/api/2/apps/%v/rules/%v", appID, ruleID), rule)
+	return err
+}
+
+func (s *AppsService) saveRule(ctx context.Context, method, u string, rule *AppRule) (int64, error) {
+	req, err := s.client.NewRequest(method, u, rule)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return 0, err
+	}
+
+	var r idResponse
+	if _, err := s.client.Do(ctx, req, &r); err != nil {
+		return 0, err
+	}
+
+	return r.ID, nil
+}
+
+// DeleteRule deletes a rule of an app.
+func (s *AppsService) DeleteRule(ctx context.Context, appID, ruleID int64) error {
+	u := fmt.Sprintf("/api/2/apps/%v/rules/%v", appID, ruleID)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// SortRules sets the order in which the rules of an app are applied.
+// ruleIDs must list all the rules of the app.
+func (s *AppsService) SortRules(ctx context.Context, appID int64, ruleIDs []int64) error {
+	u := fmt.Sprintf("/api/2/apps/%v/rules/sort", appID)
+
+	req, err := s.client.NewRequest("PUT", u, ruleIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// AppUser is a user who can launch an app.
+type AppUser struct {
+	ID        int64  `json:"id"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	FirstName string `json:"firstname"`
+	LastName  string `json:"lastname"`
+}
+
+// GetUsers returns all the users who can launch an app, walking through all the pages.
+// OneLogin grants access to apps through roles: use RolesService to assign or
+// remove the roles of the app to users.
+func (s *AppsService) GetUsers(ctx context.Context, appID int64) ([]*AppUser, error) {
+	p := newPager(s.client, fmt.Sprintf("/api/2/apps/%v/users", appID), nil)
+
+	var users []*AppUser
+	for {
+		var us []*AppUser
+		ok, err := p.next(ctx, &us)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return users, nil
+		}
+		users = append(users, us...)
+	}
+}
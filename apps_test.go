@@ -0,0 +1,65 @@
+package onelogin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestSetParameterClearsFields(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	s.HandleJSON("GET", "/api/2/apps/1", http.StatusOK, map[string]interface{}{
+		"id":           1,
+		"name":         "app",
+		"connector_id": 2,
+		"parameters": map[string]interface{}{
+			"email": map[string]interface{}{
+				"id":                        3,
+				"label":                     "Email",
+				"user_attribute_mappings":   "email",
+				"include_in_saml_assertion": true,
+				"skip_if_blank":             true,
+			},
+		},
+	})
+	s.HandleJSON("PUT", "/api/2/apps/1", http.StatusOK, map[string]interface{}{"id": 1})
+
+	c := s.Client()
+	err := c.Apps.SetParameter(context.Background(), 1, "email", onelogin.AppParameter{Label: "Email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		Parameters map[string]map[string]interface{} `json:"parameters"`
+	}
+	for _, r := range s.Requests() {
+		if r.Method == "PUT" {
+			if err := json.Unmarshal(r.Body, &body); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	p := body.Parameters["email"]
+	if p == nil {
+		t.Fatalf("got parameters %v, want the email one", body.Parameters)
+	}
+	want := map[string]interface{}{
+		"id":                        float64(3),
+		"user_attribute_mappings":   "",
+		"include_in_saml_assertion": false,
+		"skip_if_blank":             false,
+	}
+	for k, v := range want {
+		if p[k] != v {
+			t.Errorf("got %s %v, want %v", k, p[k], v)
+		}
+	}
+}
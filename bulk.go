@@ -0,0 +1,70 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// bulkChunkSize is the number of ids sent per request by the bulk methods.
+const bulkChunkSize = 100
+
+// BulkResult is the outcome of a bulk operation for one id: Err is nil when it succeeded.
+type BulkResult struct {
+	ID  int64
+	Err error
+}
+
+// BulkResults are the outcomes of a bulk operation, in the order of its ids.
+type BulkResults []*BulkResult
+
+// Failed returns the ids whose operation failed, e.g. to retry them.
+func (r BulkResults) Failed() []int64 {
+	var ids []int64
+	for _, res := range r {
+		if res.Err != nil {
+			ids = append(ids, res.ID)
+		}
+	}
+
+	return ids
+}
+
+// bulk applies fn to the ids, bulkChunkSize at a time. OneLogin reports a single
+// error for a whole request: the ids of a failed chunk are applied again one by
+// one, to tell which of them fail.
+// The returned error, if any, reports how many ids failed and wraps the first failure.
+func bulk(ctx context.Context, ids []int64, fn func(ctx context.Context, ids []int64) error) (BulkResults, error) {
+	results := make(BulkResults, 0, len(ids))
+	for start := 0; start < len(ids); start += bulkChunkSize {
+		end := start + bulkChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+
+		err := fn(ctx, chunk)
+		for _, id := range chunk {
+			if err != nil && len(chunk) > 1 && ctx.Err() == nil {
+				results = append(results, &BulkResult{ID: id, Err: fn(ctx, []int64{id})})
+			} else {
+				results = append(results, &BulkResult{ID: id, Err: err})
+			}
+		}
+	}
+
+	var first error
+	failed := 0
+	for _, res := range results {
+		if res.Err != nil {
+			if first == nil {
+				first = res.Err
+			}
+			failed++
+		}
+	}
+	if first != nil {
+		return results, fmt.Errorf("%d of %d ids failed: %w", failed, len(ids), first)
+	}
+
+	return results, nil
+}
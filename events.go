@@ -0,0 +1,233 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EventsService handles communications with the events API of OneLogin.
+type EventsService service
+
+// Event is an entry of the OneLogin audit event stream.
+type Event struct {
+	ID                 int64  `json:"id"`
+	EventTypeID        int64  `json:"event_type_id"`
+	CreatedAt          Time   `json:"created_at"`
+	AccountID          int64  `json:"account_id"`
+	UserID             int64  `json:"user_id"`
+	UserName           string `json:"user_name"`
+	ActorUserID        int64  `json:"actor_user_id"`
+	ActorUserName      string `json:"actor_user_name"`
+	ActorSystem        string `json:"actor_system"`
+	AppID              int64  `json:"app_id"`
+	AppName            string `json:"app_name"`
+	RoleID             int64  `json:"role_id"`
+	RoleName           string `json:"role_name"`
+	GroupID            int64  `json:"group_id"`
+	GroupName          string `json:"group_name"`
+	DirectoryID        int64  `json:"directory_id"`
+	IPAddr             string `json:"ipaddr"`
+	Notes              string `json:"notes"`
+	CustomMessage      string `json:"custom_message"`
+	ErrorDescription   string `json:"error_description"`
+	Resolution         string `json:"resolution"`
+	RiskScore          int64  `json:"risk_score"`
+	RiskReasons        string `json:"risk_reasons"`
+	BrowserFingerprint string `json:"browser_fingerprint"`
+}
+
+// EventListOptions filters the events returned by EventsService.List.
+type EventListOptions struct {
+	EventTypeID int64     `url:"event_type_id,omitempty"`
+	UserID      int64     `url:"user_id,omitempty"`
+	DirectoryID int64     `url:"directory_id,omitempty"`
+	Since       time.Time `url:"since,omitempty"`
+	Until       time.Time `url:"until,omitempty"`
+}
+
+type eventQuery struct {
+	EventListOptions
+	AfterCursor string `url:"after_cursor,omitempty"`
+}
+
+// List returns all the events matching opts, walking through all the pages.
+func (s *EventsService) List(ctx context.Context, opts *EventListOptions) ([]*Event, error) {
+	u := "/api/1/events"
+
+	q := &eventQuery{}
+	if opts != nil {
+		q.EventListOptions = *opts
+	}
+
+	var events []*Event
+
+	for {
+		uu, err := addOptions(u, q)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := s.client.NewRequest("GET", uu, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.client.AddAuthorization(ctx, req); err != nil {
+			return nil, err
+		}
+
+		var es []*Event
+		resp, err := s.client.Do(ctx, req, &es)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, es...)
+		if resp.PaginationAfterCursor == nil || *resp.PaginationAfterCursor == "" {
+			break
+		}
+
+		q.AfterCursor = *resp.PaginationAfterCursor
+	}
+
+	return events, nil
+}
+
+// Tail polls every interval for the new events matching opts, and sends them on
+// the returned channel in the order of their ids, until ctx is done.
+// It starts with the events since opts.Since, or now when it is zero; opts.Until
+// is ignored. Every event is sent once, even though consecutive polls overlap.
+// Tail stops at the first failed poll, sending its error on the error channel.
+// Both channels are closed once Tail stops. An interval that isn't positive is
+// reported on the error channel, without polling.
+func (s *EventsService) Tail(ctx context.Context, opts *EventListOptions, interval time.Duration) (<-chan *Event, <-chan error) {
+	events := make(chan *Event)
+	errs := make(chan error, 1)
+
+	if interval <= 0 {
+		errs <- fmt.Errorf("invalid tail interval %v", interval)
+		close(errs)
+		close(events)
+		return events, errs
+	}
+
+	q := EventListOptions{}
+	if opts != nil {
+		q = *opts
+	}
+	q.Until = time.Time{}
+	if q.Since.IsZero() {
+		q.Since = time.Now()
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		// seen holds the creation time of the events already sent which the next
+		// poll returns again, i.e. the ones created at q.Since or after.
+		seen := make(map[int64]time.Time)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			es, err := s.List(ctx, &q)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+
+			sort.Slice(es, func(i, j int) bool { return es[i].ID < es[j].ID })
+
+			latest := q.Since
+			for _, e := range es {
+				if _, ok := seen[e.ID]; ok {
+					continue
+				}
+
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+
+				seen[e.ID] = e.CreatedAt.Time
+				if e.CreatedAt.After(latest) {
+					latest = e.CreatedAt.Time
+				}
+			}
+
+			q.Since = latest
+			for id, createdAt := range seen {
+				if createdAt.Before(latest) {
+					delete(seen, id)
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// Get returns an event. ErrNotFound is returned when there is no event with the id.
+func (s *EventsService) Get(ctx context.Context, id int64) (*Event, error) {
+	u := fmt.Sprintf("/api/1/events/%v", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	if _, err := s.client.Do(ctx, req, &events); err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return events[0], nil
+}
+
+// EventType describes a type of event.
+type EventType struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Types returns the catalog of the event types.
+func (s *EventsService) Types(ctx context.Context) ([]*EventType, error) {
+	u := "/api/1/events/types"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var types []*EventType
+	if _, err := s.client.Do(ctx, req, &types); err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
@@ -0,0 +1,40 @@
+package onelogin_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestListEventsStopsOnEmptyCursor(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	s.HandleFunc("GET", "/api/1/events", func(w http.ResponseWriter, r *http.Request) {
+		id, cursor := 1, "page-2"
+		if r.URL.Query().Get("after_cursor") == "page-2" {
+			id, cursor = 2, ""
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":{"error":false,"code":200,"type":"success","message":"Success"},`+
+			`"pagination":{"before_cursor":null,"after_cursor":%q},"data":[{"id":%d}]}`, cursor, id)
+	})
+
+	c := s.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := c.Events.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 || events[0].ID != 1 || events[1].ID != 2 {
+		t.Errorf("got events %v, want the events 1 and 2", events)
+	}
+}
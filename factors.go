@@ -0,0 +1,231 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// FactorsService handles communications with the v2 MFA API of OneLogin,
+// used to manage the devices of the users.
+type FactorsService service
+
+// Device is an MFA device registered by a user.
+type Device struct {
+	ID              string `json:"device_id"`
+	UserDisplayName string `json:"user_display_name"`
+	TypeDisplayName string `json:"type_display_name"`
+	AuthFactorName  string `json:"auth_factor_name"`
+	Default         bool   `json:"default"`
+}
+
+// Registration is an ongoing enrollment of an MFA device.
+type Registration struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	UserID   int64  `json:"user_id"`
+	DeviceID string `json:"device_id"`
+
+	// TOTPURL is the otpauth:// URL (usually displayed as a QR code) to set up an authenticator app.
+	TOTPURL string `json:"totp_url"`
+	// Secret is the shared TOTP secret, for authenticator apps which can't scan the TOTPURL.
+	Secret string `json:"secret"`
+}
+
+type enrollFactorParams struct {
+	FactorID    int    `json:"factor_id"`
+	DisplayName string `json:"display_name"`
+}
+
+type verifyEnrollmentParams struct {
+	OTP string `json:"otp"`
+}
+
+// ListDevices returns the MFA devices registered by a user.
+func (s *FactorsService) ListDevices(ctx context.Context, userID int64) ([]*Device, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/devices", userID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var devices []*Device
+	if _, err := s.client.Do(ctx, req, &devices); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// EnrollFactor starts the enrollment of a device for the factor, which has to be
+// completed with VerifyEnrollment or ActivateFactor depending on the factor.
+func (s *FactorsService) EnrollFactor(ctx context.Context, userID int64, factorID int, displayName string) (*Registration, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/registrations", userID)
+
+	p := enrollFactorParams{
+		FactorID:    factorID,
+		DisplayName: displayName,
+	}
+	return s.registration(ctx, "POST", u, p)
+}
+
+// ActivateFactor returns the status of a registration, completing the enrollment
+// of the factors verified out of band such as OneLogin Protect or OneLogin Voice.
+func (s *FactorsService) ActivateFactor(ctx context.Context, userID int64, registrationID string) (*Registration, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/registrations/%s", userID, registrationID)
+
+	return s.registration(ctx, "GET", u, nil)
+}
+
+// VerifyEnrollment completes the enrollment of an OTP based factor with the OTP
+// generated or received by the device.
+func (s *FactorsService) VerifyEnrollment(ctx context.Context, userID int64, registrationID, otp string) (*Registration, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/registrations/%s", userID, registrationID)
+
+	return s.registration(ctx, "PUT", u, verifyEnrollmentParams{OTP: otp})
+}
+
+func (s *FactorsService) registration(ctx context.Context, method, u string, body interface{}) (*Registration, error) {
+	req, err := s.client.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var r Registration
+	if _, err := s.client.Do(ctx, req, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// RemoveDevice removes an MFA device of a user.
+func (s *FactorsService) RemoveDevice(ctx context.Context, userID int64, deviceID string) error {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/devices/%s", userID, deviceID)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// AuthFactor is an MFA factor enabled on the account.
+type AuthFactor struct {
+	FactorID       int    `json:"factor_id"`
+	Name           string `json:"name"`
+	AuthFactorName string `json:"auth_factor_name"`
+}
+
+// Available returns the factors enabled on the account, which devices can be enrolled for.
+func (s *FactorsService) Available(ctx context.Context) ([]*AuthFactor, error) {
+	u := "/api/2/mfa/factors"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var factors []*AuthFactor
+	if _, err := s.client.Do(ctx, req, &factors); err != nil {
+		return nil, err
+	}
+
+	return factors, nil
+}
+
+// Verification is an MFA verification issued for a device of a user.
+type Verification struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	DeviceID  string `json:"device_id"`
+	ExpiresAt Time   `json:"expires_at"`
+}
+
+type verificationParams struct {
+	DeviceID  string `json:"device_id"`
+	ExpiresIn int    `json:"expires_in,omitempty"`
+}
+
+// GenerateVerification issues a verification for one of the devices of a user,
+// sending an OTP or a push notification to it. It is valid for expiresIn seconds,
+// or the OneLogin default when zero.
+// The id of the returned verification is required to check its status.
+func (s *FactorsService) GenerateVerification(ctx context.Context, userID int64, deviceID string, expiresIn int) (*Verification, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/verifications", userID)
+
+	p := verificationParams{
+		DeviceID:  deviceID,
+		ExpiresIn: expiresIn,
+	}
+
+	req, err := s.client.NewRequest("POST", u, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var v Verification
+	if _, err := s.client.Do(ctx, req, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// MFAToken is a temporary token a user can log in with instead of verifying one
+// of their factors, e.g. after losing their device.
+type MFAToken struct {
+	Token    string `json:"mfa_token"`
+	Reusable bool   `json:"reusable"`
+	// ExpiresAt is when the token stops being accepted.
+	ExpiresAt Time   `json:"expires_at"`
+	DeviceID  string `json:"device_id"`
+}
+
+type mfaTokenParams struct {
+	ExpiresIn int `json:"expires_in,omitempty"`
+}
+
+// GenerateBypassToken issues a single use MFA token for a user, valid for
+// expiresIn seconds, or the OneLogin default when zero.
+func (s *FactorsService) GenerateBypassToken(ctx context.Context, userID int64, expiresIn int) (*MFAToken, error) {
+	u := fmt.Sprintf("/api/2/mfa/users/%v/mfa_token", userID)
+
+	req, err := s.client.NewRequest("POST", u, mfaTokenParams{ExpiresIn: expiresIn})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var t MFAToken
+	if _, err := s.client.Do(ctx, req, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -5,9 +5,11 @@ import "golang.org/x/net/context"
 // GroupService deals with OneLogin groups.
 type GroupService service
 
+// Group represents a OneLogin group.
 type Group struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Reference string `json:"reference"`
 }
 
 // GetGroups returns all the OneLogin groups.
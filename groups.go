@@ -0,0 +1,56 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupsService handles communications with the v2 groups API of OneLogin.
+type GroupsService service
+
+// List returns all the OneLogin groups, walking through all the pages.
+func (s *GroupsService) List(ctx context.Context, opts *ListOptions) ([]*Group, error) {
+	p := newPager(s.client, "/api/2/groups", opts)
+
+	var groups []*Group
+	for {
+		var gs []*Group
+		ok, err := p.next(ctx, &gs)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return groups, nil
+		}
+		groups = append(groups, gs...)
+	}
+}
+
+// Get returns a OneLogin group.
+func (s *GroupsService) Get(ctx context.Context, id int64) (*Group, error) {
+	return s.GetWithOptions(ctx, id, nil)
+}
+
+// GetWithOptions returns a OneLogin group, configured by opts.
+func (s *GroupsService) GetWithOptions(ctx context.Context, id int64, opts *GetOptions) (*Group, error) {
+	u, err := addOptions(fmt.Sprintf("/api/2/groups/%v", id), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var group Group
+	if _, err := s.client.Do(ctx, req, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
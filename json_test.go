@@ -0,0 +1,177 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestResponsesRoundTrip decodes a response of each type, as documented by the
+// OneLogin API with all the fields set, and checks encoding it back gives the
+// same JSON: a mislabeled tag either drops a field or renames it.
+func TestResponsesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		data string
+	}{
+		{"App", &App{}, `{
+			"id": 1, "name": "app", "description": "description", "notes": "notes",
+			"connector_id": 2, "policy_id": 3, "brand_id": 4, "icon_url": "https://example.com/icon.png",
+			"visible": true, "auth_method": 2, "tab_id": 5, "role_ids": [6, 7],
+			"created_at": "2020-01-02T03:04:05Z", "updated_at": "2020-01-03T03:04:05.5Z",
+			"parameters": {"email": {
+				"id": 8, "label": "Email", "user_attribute_mappings": "email",
+				"user_attribute_macros": "{email}", "attributes_transformations": "none",
+				"default_values": "default", "values": "values", "skip_if_blank": true,
+				"provisioned_entitlements": true, "include_in_saml_assertion": true
+			}},
+			"provisioning": {"enabled": true},
+			"configuration": {"signature_algorithm": "SHA-256"},
+			"sso": {"metadata_url": "https://example.com/metadata"}
+		}`},
+		{"Connector", &Connector{}, `{
+			"id": 1, "name": "SAML", "icon_url": "https://example.com/icon.png",
+			"auth_method": 2, "allows_new_parameters": true
+		}`},
+		{"AppRule", &AppRule{}, `{
+			"id": 1, "name": "rule", "enabled": true, "match": "all", "position": 2,
+			"conditions": [{"source": "has_role", "operator": "ri", "value": "3"}],
+			"actions": [{"action": "set_groups", "value": ["admins"], "expression": ".*", "macro": "{email}"}]
+		}`},
+		{"AppUser", &AppUser{}, `{
+			"id": 1, "email": "ada@example.com", "username": "ada", "firstname": "Ada", "lastname": "Lovelace"
+		}`},
+		{"Event", &Event{}, `{
+			"id": 1, "event_type_id": 5, "created_at": "2020-01-02T03:04:05Z", "account_id": 2,
+			"user_id": 3, "user_name": "Ada Lovelace", "actor_user_id": 4, "actor_user_name": "Admin",
+			"actor_system": "API", "app_id": 6, "app_name": "app", "role_id": 7, "role_name": "role",
+			"group_id": 8, "group_name": "group", "directory_id": 9, "ipaddr": "192.0.2.1",
+			"notes": "notes", "custom_message": "message", "error_description": "error",
+			"resolution": "resolution", "risk_score": 10, "risk_reasons": "new device",
+			"browser_fingerprint": "fingerprint"
+		}`},
+		{"EventType", &EventType{}, `{"id": 5, "name": "USER_LOGGED_INTO_ONELOGIN", "description": "description"}`},
+		{"Device", &Device{}, `{
+			"device_id": "1", "user_display_name": "phone", "type_display_name": "OneLogin Protect",
+			"auth_factor_name": "OneLogin", "default": true
+		}`},
+		{"Registration", &Registration{}, `{
+			"id": "1", "status": "pending", "user_id": 2, "device_id": "3",
+			"totp_url": "otpauth://totp/ada", "secret": "secret"
+		}`},
+		{"AuthFactor", &AuthFactor{}, `{"factor_id": 1, "name": "OneLogin Protect", "auth_factor_name": "OneLogin"}`},
+		{"Verification", &Verification{}, `{
+			"id": "1", "status": "pending", "device_id": "2", "expires_at": "2020-01-02T03:04:05Z"
+		}`},
+		{"MFAToken", &MFAToken{}, `{
+			"mfa_token": "token", "reusable": true, "expires_at": "2020-01-02T03:04:05Z", "device_id": "1"
+		}`},
+		{"Group", &Group{}, `{"id": 1, "name": "group", "reference": "reference"}`},
+		{"Mapping", &Mapping{}, `{
+			"id": 1, "name": "mapping", "enabled": true, "match": "any", "position": 2,
+			"conditions": [{"source": "email", "operator": "~", "value": "@example.com"}],
+			"actions": [{"action": "add_role", "value": ["3"]}]
+		}`},
+		{"dryRunResult", &dryRunResult{}, `{
+			"user": {"id": 1, "name": "Ada Lovelace", "email": "ada@example.com"}, "mapped": true
+		}`},
+		{"MappingValue", &MappingValue{}, `{"name": "Email", "value": "email"}`},
+		{"getTokenResponse", &getTokenResponse{}, `{
+			"access_token": "access", "account_id": 1, "created_at": "2020-01-02T03:04:05Z",
+			"expires_in": 36000, "refresh_token": "refresh", "token_type": "bearer"
+		}`},
+		{"authenticateResponse", &authenticateResponse{}, `{
+			"expires_at": "2020-01-02T03:04:05Z", "return_to_url": "https://example.com",
+			"session_token": "session", "status": "Authenticated",
+			"user": {"id": 1, "username": "ada", "email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace"},
+			"state_token": "state", "callback_url": "https://example.com/callback",
+			"devices": [{"device_type": "Google Authenticator", "device_id": 2}]
+		}`},
+		{"mfaResponse", &mfaResponse{}, `{
+			"expires_at": "2020-01-02T03:04:05Z", "state_token": "state", "session_token": "session",
+			"status": "Authenticated", "return_to_url": "https://example.com",
+			"user": {"id": 1, "username": "ada", "email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace"}
+		}`},
+		{"samlResponse", &samlResponse{}, `{
+			"data": "PHNhbWxwOlJlc3BvbnNlLz4=", "message": "MFA is required for this user",
+			"state_token": "state", "callback_url": "https://example.com/callback",
+			"devices": [{"device_type": "Yubico YubiKey", "device_id": 2}],
+			"user": {"id": 1, "username": "ada", "email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace"}
+		}`},
+		{"idResponse", &idResponse{}, `{"id": 1}`},
+		{"Privilege", &Privilege{}, `{
+			"id": "1", "name": "privilege", "description": "description",
+			"privilege": {"Version": "2018-05-18", "Statement": [
+				{"Effect": "Allow", "Action": ["users:List"], "Scope": ["*"]}
+			]}
+		}`},
+		{"RateLimit", &RateLimit{}, `{"X-RateLimit-Limit": 5000, "X-RateLimit-Remaining": 4999, "X-RateLimit-Reset": 3600}`},
+		{"RiskScore", &RiskScore{}, `{"score": 50, "triggers": ["new device"], "messages": ["Unknown device"]}`},
+		{"Role", &Role{}, `{"id": 1, "name": "role", "apps": [2], "users": [3], "admins": [4]}`},
+		{"RoleUser", &RoleUser{}, `{"id": 1, "name": "Ada Lovelace", "email": "ada@example.com", "username": "ada"}`},
+		{"Hook", &Hook{}, `{
+			"id": "1", "type": "pre-authentication", "disabled": true, "runtime": "nodejs18.x",
+			"context_version": "1.0.0", "retries": 2, "timeout": 1, "env_vars": ["API_KEY"],
+			"packages": {"axios": "1.1.3"}, "function": "ZnVuY3Rpb24=", "status": "ready",
+			"created_at": "2020-01-02T03:04:05Z", "updated_at": "2020-01-03T03:04:05Z"
+		}`},
+		{"EnvVar", &EnvVar{}, `{
+			"id": "1", "name": "API_KEY", "created_at": "2020-01-02T03:04:05Z", "updated_at": "2020-01-03T03:04:05Z"
+		}`},
+		{"User", &User{}, `{
+			"activated_at": "2020-01-01T03:04:05Z", "created_at": "2020-01-02T03:04:05Z",
+			"email": "ada@example.com", "username": "ada", "firstname": "Ada", "group_id": 1, "id": 2,
+			"invalid_login_attempts": 3, "invitation_sent_at": "2020-01-03T03:04:05Z",
+			"last_login": "2020-01-04T03:04:05Z", "lastname": "Lovelace",
+			"locked_until": "2020-01-05T03:04:05Z", "notes": "notes", "openid_name": "ada",
+			"locale_code": "en", "password_changed_at": "2020-01-06T03:04:05Z", "phone": "+15555550100",
+			"status": 1, "updated_at": "2020-01-07T03:04:05Z", "distinguished_name": "CN=Ada",
+			"external_id": "external", "directory_id": 4, "member_of": ["CN=Admins"],
+			"samaccountname": "ada", "userprincipalname": "ada@example.com", "manager_ad_id": 5,
+			"role_id": [6, 7], "custom_attributes": {"employee_id": "8"}
+		}`},
+		{"UserApp", &UserApp{}, `{
+			"id": 1, "name": "app", "icon_url": "https://example.com/icon.png", "login_id": 2,
+			"provisioning_enabled": true, "provisioning_status": "provisioned", "provisioning_state": "enabled"
+		}`},
+		{"Token", &Token{}, `{
+			"access_token": "access", "refresh_token": "refresh", "account_id": 1,
+			"created_at": "2020-01-02T03:04:05Z", "expires_in": 36000, "token_type": "bearer"
+		}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), tt.v); err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tt.data), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s, want %s", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestMFAResponseTokens(t *testing.T) {
+	var r mfaResponse
+	if err := json.Unmarshal([]byte(`{"state_token":"state","session_token":"session"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.StateToken != "state" || r.SessionToken != "session" {
+		t.Errorf("got state token %q and session token %q, want %q and %q", r.StateToken, r.SessionToken, "state", "session")
+	}
+}
@@ -0,0 +1,132 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// A Logger is notified of every request sent by a client configured with WithLogger.
+// The ctx it receives is the one of the request, so the Logger can also be used
+// to annotate the current tracing span.
+type Logger interface {
+	LogRequest(ctx context.Context, r *RequestLog)
+}
+
+// LoggerFunc adapts a function into a Logger.
+type LoggerFunc func(ctx context.Context, r *RequestLog)
+
+// LogRequest calls f(ctx, r).
+func (f LoggerFunc) LogRequest(ctx context.Context, r *RequestLog) {
+	f(ctx, r)
+}
+
+// RequestLog describes a request sent to OneLogin.
+// The Authorization header and the secret fields of the body are redacted.
+type RequestLog struct {
+	Method   string
+	URL      string
+	Path     string
+	Header   http.Header
+	Body     string
+	Duration time.Duration
+
+	// StatusCode is zero when no response has been received, in which case Err is set.
+	StatusCode int
+	Err        error
+
+	// RateLimit is read from the X-RateLimit-* headers of the response, nil when they are absent.
+	RateLimit *RateLimit
+}
+
+const redacted = "REDACTED"
+
+// secretFields are the request body fields which are never logged.
+var secretFields = map[string]bool{
+	"password":              true,
+	"password_confirmation": true,
+	"password_salt":         true,
+	"client_secret":         true,
+	"access_token":          true,
+	"refresh_token":         true,
+	"otp_token":             true,
+	"otp":                   true,
+	"value":                 true,
+	"session_token":         true,
+	"state_token":           true,
+}
+
+// logRequest reports the request to the logger of the client, if any.
+func (c *Client) logRequest(ctx context.Context, req *http.Request, resp *http.Response, err error, d time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	r := &RequestLog{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Path:     req.URL.Path,
+		Header:   req.Header.Clone(),
+		Duration: d,
+		Err:      err,
+	}
+	if resp != nil {
+		r.StatusCode = resp.StatusCode
+		r.RateLimit = parseRateLimit(resp.Header)
+	}
+	if r.Header.Get("Authorization") != "" {
+		r.Header.Set("Authorization", redacted)
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			r.Body = redactBody(data)
+		}
+	}
+
+	c.logger.LogRequest(ctx, r)
+}
+
+// redactBody replaces the values of the secret fields of a JSON body, at any
+// depth. Bodies which aren't JSON are entirely redacted.
+func redactBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return redacted
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+
+	return string(b)
+}
+
+// redactValue replaces the values of the secret fields of the objects within v.
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v {
+			if secretFields[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(vv)
+			}
+		}
+	case []interface{}:
+		for i, vv := range v {
+			v[i] = redactValue(vv)
+		}
+	}
+
+	return v
+}
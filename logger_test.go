@@ -0,0 +1,45 @@
+package onelogin
+
+import "testing"
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "top level",
+			body: `{"device_id":"1","state_token":"state","otp_token":"123456"}`,
+			want: `{"device_id":"1","otp_token":"REDACTED","state_token":"REDACTED"}`,
+		},
+		{
+			name: "session token",
+			body: `{"session_token":"session"}`,
+			want: `{"session_token":"REDACTED"}`,
+		},
+		{
+			name: "nested",
+			body: `{"user":{"email":"ada@example.com","password":"secret"}}`,
+			want: `{"user":{"email":"ada@example.com","password":"REDACTED"}}`,
+		},
+		{
+			name: "array",
+			body: `[{"name":"API_KEY","value":"secret"},1]`,
+			want: `[{"name":"API_KEY","value":"REDACTED"},1]`,
+		},
+		{
+			name: "not json",
+			body: `session_token=session`,
+			want: redacted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
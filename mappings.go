@@ -0,0 +1,232 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// MappingsService handles communications with the v2 user mappings API of OneLogin.
+// Mappings are the rules automatically assigning roles, groups or attributes to users.
+type MappingsService service
+
+// Mapping is a rule applying Actions to the users matching its Conditions.
+type Mapping struct {
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Match is either "all" or "any" of the conditions.
+	Match string `json:"match"`
+	// Position is the order in which the enabled mappings are applied, nil for disabled mappings.
+	Position   *int64              `json:"position,omitempty"`
+	Conditions []*MappingCondition `json:"conditions"`
+	Actions    []*MappingAction    `json:"actions"`
+}
+
+// MappingCondition matches the users whose Source compares to Value with Operator.
+type MappingCondition struct {
+	Source   string `json:"source"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// MappingAction sets the Value of the Action on the matching users.
+type MappingAction struct {
+	Action string   `json:"action"`
+	Value  []string `json:"value"`
+}
+
+// MappingListOptions filters the mappings returned by MappingsService.List.
+type MappingListOptions struct {
+	ListOptions
+
+	Enabled      *bool  `url:"enabled,omitempty"`
+	HasCondition string `url:"has_condition,omitempty"`
+	HasAction    string `url:"has_action,omitempty"`
+}
+
+// List returns all the mappings matching opts.
+func (s *MappingsService) List(ctx context.Context, opts *MappingListOptions) ([]*Mapping, error) {
+	p := newPager(s.client, "/api/2/mappings", opts)
+
+	var mappings []*Mapping
+	for {
+		var ms []*Mapping
+		ok, err := p.next(ctx, &ms)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return mappings, nil
+		}
+		mappings = append(mappings, ms...)
+	}
+}
+
+// Get returns a mapping.
+func (s *MappingsService) Get(ctx context.Context, id int64) (*Mapping, error) {
+	u := fmt.Sprintf("/api/2/mappings/%v", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var mapping Mapping
+	if _, err := s.client.Do(ctx, req, &mapping); err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+// Create creates a mapping and returns its id.
+func (s *MappingsService) Create(ctx context.Context, mapping *Mapping) (int64, error) {
+	return s.save(ctx, "POST", "/api/2/mappings", mapping)
+}
+
+// Update replaces a mapping. All its fields are sent, so mapping should be
+// the one returned by Get, modified.
+func (s *MappingsService) Update(ctx context.Context, id int64, mapping *Mapping) error {
+	_, err := s.save(ctx, "PUT", fmt.Sprintf("/api/2/mappings/%v", id), mapping)
+	return err
+}
+
+func (s *MappingsService) save(ctx context.Context, method, u string, mapping *Mapping) (int64, error) {
+	req, err := s.client.NewRequest(method, u, mapping)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return 0, err
+	}
+
+	var r idResponse
+	if _, err := s.client.Do(ctx, req, &r); err != nil {
+		return 0, err
+	}
+
+	return r.ID, nil
+}
+
+// Delete deletes a mapping.
+func (s *MappingsService) Delete(ctx context.Context, id int64) error {
+	u := fmt.Sprintf("/api/2/mappings/%v", id)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Sort sets the order in which the enabled mappings are applied.
+// ids must list all the enabled mappings.
+func (s *MappingsService) Sort(ctx context.Context, ids []int64) error {
+	u := "/api/2/mappings/sort"
+
+	req, err := s.client.NewRequest("PUT", u, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// MappingUser is a user a mapping applies to, as returned by DryRun.
+type MappingUser struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type dryRunResult struct {
+	User   *MappingUser `json:"user"`
+	Mapped bool         `json:"mapped"`
+}
+
+// DryRun returns the users, among userIDs, that the mapping would apply to,
+// without applying it.
+func (s *MappingsService) DryRun(ctx context.Context, id int64, userIDs []int64) ([]*MappingUser, error) {
+	u := fmt.Sprintf("/api/2/mappings/%v/dryrun", id)
+
+	req, err := s.client.NewRequest("POST", u, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var results []*dryRunResult
+	if _, err := s.client.Do(ctx, req, &results); err != nil {
+		return nil, err
+	}
+
+	var users []*MappingUser
+	for _, r := range results {
+		if r.Mapped && r.User != nil {
+			users = append(users, r.User)
+		}
+	}
+
+	return users, nil
+}
+
+// MappingValue is a valid value of a condition, operator or condition value,
+// along its human readable name.
+type MappingValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Conditions returns the sources the mapping conditions can be based on.
+func (s *MappingsService) Conditions(ctx context.Context) ([]*MappingValue, error) {
+	return s.values(ctx, "/api/2/mappings/conditions")
+}
+
+// Operators returns the operators available for the condition source conditionValue.
+func (s *MappingsService) Operators(ctx context.Context, conditionValue string) ([]*MappingValue, error) {
+	return s.values(ctx, fmt.Sprintf("/api/2/mappings/conditions/%s/operators", url.PathEscape(conditionValue)))
+}
+
+// Values returns the values available for the condition source conditionValue.
+// Sources accepting free text have no values.
+func (s *MappingsService) Values(ctx context.Context, conditionValue string) ([]*MappingValue, error) {
+	return s.values(ctx, fmt.Sprintf("/api/2/mappings/conditions/%s/values", url.PathEscape(conditionValue)))
+}
+
+func (s *MappingsService) values(ctx context.Context, u string) ([]*MappingValue, error) {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var values []*MappingValue
+	if _, err := s.client.Do(ctx, req, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
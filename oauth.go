@@ -1,9 +1,12 @@
 package onelogin
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -11,8 +14,85 @@ import (
 var (
 	AuthenticationFailed = errors.New("authentication failed")
 	MFA                  = errors.New("mfa verification required")
+	ErrMFAPending        = errors.New("mfa verification pending")
+
+	ErrEmptyTokenResponse = errors.New("empty token response")
+	ErrAccessTokenExpired = errors.New("provided access token expired")
+)
+
+// Sentinel errors matched by the TokenError of a failed token issuance or refresh,
+// with errors.Is.
+var (
+	// ErrInvalidClient is matched when the client credentials, or the password or
+	// the refresh token of the grant, are rejected: retrying won't help.
+	ErrInvalidClient = errors.New("invalid client credentials")
+	// ErrTokenRateLimited is matched when too many tokens have been issued:
+	// the issuance can be retried later.
+	ErrTokenRateLimited = errors.New("token issuance rate limited")
+	// ErrAccountSuspended is matched when OneLogin says the account, or its API
+	// access, is suspended. The other 403 responses only match ErrForbidden.
+	ErrAccountSuspended = errors.New("account suspended")
 )
 
+// A TokenError is returned when OneLogin refuses to issue or refresh a token.
+// It matches ErrInvalidClient, ErrTokenRateLimited or ErrAccountSuspended with
+// errors.Is depending on the cause, and unwraps to the *APIError of the response,
+// so the sentinel errors of its status code, e.g. ErrForbidden, match too.
+type TokenError struct {
+	Err error
+
+	cause error
+}
+
+func (e *TokenError) Error() string {
+	if e.cause == nil {
+		return fmt.Sprintf("token issuance failed: %v", e.Err)
+	}
+	return fmt.Sprintf("token issuance failed: %v: %v", e.cause, e.Err)
+}
+
+// Unwrap returns the *APIError of the response.
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the cause of e.
+func (e *TokenError) Is(target error) bool {
+	return e.cause != nil && target == e.cause
+}
+
+// newTokenError classifies the *APIError of a token request.
+func newTokenError(e *APIError) *TokenError {
+	t := &TokenError{Err: e}
+
+	desc := strings.ToLower(e.Type + " " + e.Message)
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		t.cause = ErrTokenRateLimited
+	case strings.Contains(desc, "suspended"):
+		t.cause = ErrAccountSuspended
+	case e.StatusCode == http.StatusUnauthorized ||
+		strings.Contains(desc, "invalid_client") || strings.Contains(desc, "invalid_grant"):
+		t.cause = ErrInvalidClient
+	}
+
+	return t
+}
+
+// A TokenRefreshError is returned when an expired oauth token couldn't be refreshed.
+type TokenRefreshError struct {
+	Err error
+}
+
+func (e *TokenRefreshError) Error() string {
+	return fmt.Sprintf("token refresh failed: %v", e.Err)
+}
+
+// Unwrap returns the error which caused the refresh to fail.
+func (e *TokenRefreshError) Unwrap() error {
+	return e.Err
+}
+
 // OauthService handles communications with the authentication related methods on OneLogin.
 type OauthService service
 
@@ -26,19 +106,52 @@ type issueTokenParams struct {
 	GrantType    string `json:"grant_type"`
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+}
+
+// Grant types issuing the tokens of the client.
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// GrantParams holds the parameters required by the grant types: the Username
+// and Password for GrantPassword, and the RefreshToken for GrantRefreshToken.
+type GrantParams struct {
+	Username     string
+	Password     string
+	RefreshToken string
+}
+
+type revokeTokenParams struct {
+	AccessToken string `json:"access_token"`
 }
 
 type getTokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	AccountID    int    `json:"account_id"`
-	CreatedAt    string `json:"created_at"`
-	ExpiresIn    int64  `json:"expires_in"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
+	AccessToken  string  `json:"access_token"`
+	AccountID    flexInt `json:"account_id"`
+	CreatedAt    Time    `json:"created_at"`
+	ExpiresIn    flexInt `json:"expires_in"`
+	RefreshToken string  `json:"refresh_token"`
+	TokenType    string  `json:"token_type"`
+}
+
+// withCreatedAt defaults the creation time of the token to now, when the
+// response has none: a zero creation time would make the token look expired.
+func (r *getTokenResponse) withCreatedAt(now time.Time) *getTokenResponse {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt.Time = now
+	}
+
+	return r
 }
 
 // An oauthToken authenticates request to OneLogin.
 // It is valid for 3600 seconds, and can be renewed.
+// A token is never modified once issued: the client replaces it instead, while
+// holding its lock.
 type oauthToken struct {
 	AccessToken string
 	AccountID   int
@@ -48,41 +161,130 @@ type oauthToken struct {
 
 	refreshToken string
 	client       *Client
+
+	// provided is set when the token was given with WithAccessToken, rather than issued.
+	provided bool
+}
+
+// newOauthToken restores an oauthToken from a persisted Token.
+func newOauthToken(c *Client, t *Token) *oauthToken {
+	return &oauthToken{
+		AccessToken:  t.AccessToken,
+		AccountID:    t.AccountID,
+		CreatedAt:    t.CreatedAt,
+		ExpiresIn:    t.ExpiresIn,
+		TokenType:    t.TokenType,
+		refreshToken: t.RefreshToken,
+		client:       c,
+	}
+}
+
+// token returns the Token to persist the oauthToken.
+func (t *oauthToken) token() *Token {
+	return &Token{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.refreshToken,
+		AccountID:    t.AccountID,
+		CreatedAt:    t.CreatedAt,
+		ExpiresIn:    t.ExpiresIn,
+		TokenType:    t.TokenType,
+	}
 }
 
 // isExpired check the OauthToken validity.
+// The token is considered expired a little before its actual expiration, to
+// account for clock skew and avoid expiring in the middle of a request.
 func (t *oauthToken) isExpired() bool {
-	return time.Now().UTC().Add(-time.Second * time.Duration(t.ExpiresIn)).After(t.CreatedAt.UTC())
+	skew, now := defaultTokenExpirySkew, time.Now()
+	if t.client != nil {
+		skew, now = t.client.tokenExpirySkew, t.client.now()
+	}
+
+	return now.After(t.CreatedAt.Add(time.Duration(t.ExpiresIn)*time.Second - skew))
 }
 
-// refresh the token. The current token gets updates with new valid values.
-func (t *oauthToken) refresh(ctx context.Context) error {
+// refresh the token. It returns a new token, leaving the current one untouched
+// so it is never seen half updated.
+func (t *oauthToken) refresh(ctx context.Context) (*oauthToken, error) {
 	u := "/auth/oauth2/token"
 	b := issueTokenParams{
-		GrantType:    "refresh_token",
+		GrantType:    GrantRefreshToken,
 		AccessToken:  t.AccessToken,
 		RefreshToken: t.refreshToken,
 	}
 	req, err := t.client.NewRequest("POST", u, b)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var r []getTokenResponse
-	_, err = t.client.Do(ctx, req, &r)
+	r, err := t.client.doTokenRequest(ctx, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	createdAt, _ := time.Parse(time.RFC3339Nano, r[0].CreatedAt)
-	t.AccessToken = r[0].AccessToken
-	t.AccountID = r[0].AccountID
-	t.CreatedAt = createdAt
-	t.ExpiresIn = r[0].ExpiresIn
-	t.TokenType = r[0].TokenType
-	t.refreshToken = r[0].RefreshToken
+	token := &oauthToken{
+		AccessToken:  r.AccessToken,
+		AccountID:    int(r.AccountID),
+		CreatedAt:    r.CreatedAt.Time,
+		ExpiresIn:    int64(r.ExpiresIn),
+		TokenType:    r.TokenType,
+		refreshToken: r.RefreshToken,
+		client:       t.client,
+		provided:     t.provided,
+	}
 
-	return nil
+	return token, nil
+}
+
+// doTokenRequest sends a request issuing a token, and decodes the token.
+// The v1 endpoint returns the token wrapped into an array, whereas the v2
+// endpoint returns it as a bare object: both are supported.
+func (c *Client) doTokenRequest(ctx context.Context, req *http.Request) (*getTokenResponse, error) {
+	var raw json.RawMessage
+	if _, err := c.Do(ctx, req, &raw); err != nil {
+		var e *APIError
+		if errors.As(err, &e) {
+			return nil, newTokenError(e)
+		}
+		return nil, err
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, ErrEmptyTokenResponse
+	}
+
+	if raw[0] == '[' {
+		var r []*getTokenResponse
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("decoding token response: %w", err)
+		}
+		if len(r) == 0 || r[0] == nil {
+			return nil, ErrEmptyTokenResponse
+		}
+		return r[0].withCreatedAt(c.now()), nil
+	}
+
+	var r getTokenResponse
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+	if r.AccessToken == "" {
+		return nil, ErrEmptyTokenResponse
+	}
+
+	return r.withCreatedAt(c.now()), nil
+}
+
+// addClientCredentials authenticates req with the client_id and client_secret
+// of the client, either with OneLogin's custom scheme or HTTP Basic.
+func (c *Client) addClientCredentials(req *http.Request) {
+	if c.basicAuth {
+		req.SetBasicAuth(c.clientID, c.clientSecret)
+		return
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("client_id:%s,client_secret:%s", c.clientID, c.clientSecret))
 }
 
 // getToken issues a new token.
@@ -90,36 +292,71 @@ func (s *OauthService) getToken(ctx context.Context) (*oauthToken, error) {
 	u := "/auth/oauth2/token"
 
 	b := issueTokenParams{
-		GrantType: "client_credentials",
+		GrantType:    s.client.grantType,
+		Username:     s.client.grantParams.Username,
+		Password:     s.client.grantParams.Password,
+		RefreshToken: s.client.grantParams.RefreshToken,
 	}
 	req, err := s.client.NewRequest("POST", u, b)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", fmt.Sprintf("client_id: %s, client_secret: %s", s.client.clientID, s.client.clientSecret))
+	s.client.addClientCredentials(req)
 
-	var r []getTokenResponse
-	_, err = s.client.Do(ctx, req, &r)
+	r, err := s.client.doTokenRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	createdAt, _ := time.Parse(time.RFC3339Nano, r[0].CreatedAt)
 	token := &oauthToken{
-		AccessToken:  r[0].AccessToken,
-		AccountID:    r[0].AccountID,
-		CreatedAt:    createdAt,
-		ExpiresIn:    r[0].ExpiresIn,
-		TokenType:    r[0].TokenType,
-		refreshToken: r[0].RefreshToken,
+		AccessToken:  r.AccessToken,
+		AccountID:    int(r.AccountID),
+		CreatedAt:    r.CreatedAt.Time,
+		ExpiresIn:    int64(r.ExpiresIn),
+		TokenType:    r.TokenType,
+		refreshToken: r.RefreshToken,
 		client:       s.client,
 	}
 
 	return token, nil
 }
 
+// RevokeToken revokes the token currently used by the client, and clears it.
+// A new token is issued on the next request.
+// It is a no-op when no token has been issued yet.
+func (s *OauthService) RevokeToken(ctx context.Context) error {
+	s.client.Lock()
+	defer s.client.Unlock()
+
+	if s.client.oauthToken == nil {
+		return nil
+	}
+
+	u := "/auth/oauth2/revoke"
+
+	b := revokeTokenParams{
+		AccessToken: s.client.oauthToken.AccessToken,
+	}
+	req, err := s.client.NewRequest("POST", u, b)
+	if err != nil {
+		return err
+	}
+	s.client.addClientCredentials(req)
+
+	if _, err := s.client.Do(ctx, req, nil); err != nil {
+		return err
+	}
+
+	s.client.oauthToken = nil
+	if s.client.tokenStore != nil {
+		return s.client.tokenStore.Save(nil)
+	}
+
+	return nil
+}
+
 type authenticateResponse struct {
-	ExpiresAt    string             `json:"expires_at"`
+	ExpiresAt    Time               `json:"expires_at"`
 	ReturnToURL  string             `json:"return_to_url"`
 	SessionToken string             `json:"session_token"`
 	Status       string             `json:"status"`
@@ -138,37 +375,118 @@ type AuthenticatedUser struct {
 	LastName    string           `json:"lastname"`
 	Devices     []*MFADevice     `json:"-"`
 	MFAResponse *MFAVerification `json:"-"`
+
+	// SessionToken is set once the user is fully authenticated, and can be
+	// used to establish a session until ExpiresAt.
+	SessionToken string    `json:"-"`
+	ExpiresAt    time.Time `json:"-"`
 }
 
+// mfaResponse is the response of the verification of a factor: the state_token
+// of a pending verification, and the session_token once the user is authenticated.
 type mfaResponse struct {
-	ExpiresAt    string             `json:"expires_at"`
-	SessionToken string             `json:"state_token"`
+	ExpiresAt    Time               `json:"expires_at"`
+	StateToken   string             `json:"state_token"`
+	SessionToken string             `json:"session_token"`
 	Status       string             `json:"status"`
 	ReturnToURL  string             `json:"return_to_url"`
 	User         *AuthenticatedUser `json:"user"`
 }
 
+type verifyFactorParams struct {
+	DeviceID    int    `json:"device_id,string"`
+	StateToken  string `json:"state_token"`
+	OTPToken    string `json:"otp_token,omitempty"`
+	DoNotNotify bool   `json:"do_not_notify,omitempty"`
+}
+
 // MFADevice describes an MFA device
 type MFADevice struct {
 	Type string `json:"device_type"`
 	ID   int    `json:"device_id"`
 }
 
+// Types of the MFA devices.
+const (
+	MFADeviceOneLoginProtect     = "OneLogin Protect"
+	MFADeviceOneLoginSMS         = "OneLogin SMS"
+	MFADeviceOneLoginEmail       = "OneLogin Email"
+	MFADeviceOneLoginVoice       = "OneLogin Voice"
+	MFADeviceGoogleAuthenticator = "Google Authenticator"
+	MFADeviceYubiKey             = "Yubico YubiKey"
+	MFADeviceDuo                 = "Duo Security"
+	MFADeviceRSASecurID          = "RSA SecurID"
+	MFADeviceSymantecVIP         = "Symantec VIP"
+	MFADeviceAuthy               = "Authy"
+)
+
+// IsPush reports whether the device is verified out of band, by approving a push
+// notification or answering a call. Such devices are verified with TriggerFactor
+// and PollFactor (or WaitForFactor).
+func (d *MFADevice) IsPush() bool {
+	switch d.Type {
+	case MFADeviceOneLoginProtect, MFADeviceOneLoginVoice, MFADeviceDuo:
+		return true
+	}
+
+	return false
+}
+
+// RequiresOTP reports whether VerifyFactor needs the OTP generated or received by the device.
+func (d *MFADevice) RequiresOTP() bool {
+	return !d.IsPush()
+}
+
+// AuthenticateOptions configures AuthenticateWithOptions.
+type AuthenticateOptions struct {
+	// Subdomain overrides the subdomain of the client, for accounts with several brands.
+	Subdomain string
+
+	// IP and UserAgent are the ones of the browser of the user, forwarded in the
+	// X-Forwarded-For and User-Agent headers so the policies and the risk engine
+	// of OneLogin assess the user rather than the server calling the API.
+	// OneLogin doesn't take the device fingerprint when authenticating: send it
+	// with the RiskService instead.
+	IP        string
+	UserAgent string
+}
+
 // Authenticate a user from an email(or username) and a password.
-// It returns nil on success.
+// It returns nil on success. ErrMissingSubdomain is returned when the client has
+// no subdomain.
 func (s *OauthService) Authenticate(ctx context.Context, emailOrUsername string, password string) (user *AuthenticatedUser, err error) {
+	return s.AuthenticateWithOptions(ctx, emailOrUsername, password, nil)
+}
+
+// AuthenticateWithOptions authenticates a user like Authenticate, configured by opts.
+func (s *OauthService) AuthenticateWithOptions(ctx context.Context, emailOrUsername string, password string, opts *AuthenticateOptions) (user *AuthenticatedUser, err error) {
 	u := "/api/1/login/auth"
 
+	if opts == nil {
+		opts = &AuthenticateOptions{}
+	}
+
+	subdomain, err := s.client.subdomainOr(opts.Subdomain)
+	if err != nil {
+		return nil, err
+	}
+
 	a := authenticationParams{
 		Username:  emailOrUsername,
 		Password:  password,
-		Subdomain: s.client.subdomain,
+		Subdomain: subdomain,
 	}
 
 	req, err := s.client.NewRequest("POST", u, a)
 	if err != nil {
 		return nil, err
 	}
+	if opts.IP != "" {
+		req.Header.Set("X-Forwarded-For", opts.IP)
+	}
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	}
 
 	if err := s.client.AddAuthorization(ctx, req); err != nil {
 		return nil, err
@@ -180,19 +498,139 @@ func (s *OauthService) Authenticate(ctx context.Context, emailOrUsername string,
 		return nil, err
 	}
 
-	if len(d) != 1 {
-		err = AuthenticationFailed
-	} else if (d[0].User != nil) {
-		user = d[0].User
-		if (strings.HasSuffix(d[0].CallbackURL, "verify_factor")) {
-			user.Devices = d[0].Devices
-			user.MFAResponse = &MFAVerification{
-				StateToken: d[0].StateToken,
-			}
-		} else {
-			err = AuthenticationFailed
+	if len(d) != 1 || d[0].User == nil {
+		return nil, AuthenticationFailed
+	}
+
+	user = d[0].User
+	if strings.HasSuffix(d[0].CallbackURL, "verify_factor") {
+		// The session token is only issued once VerifyFactor completes.
+		user.Devices = d[0].Devices
+		user.MFAResponse = &MFAVerification{
+			StateToken: d[0].StateToken,
 		}
+	} else if d[0].SessionToken != "" {
+		user.SessionToken = d[0].SessionToken
+		user.ExpiresAt = d[0].ExpiresAt.Time
+	} else {
+		err = AuthenticationFailed
 	}
 
 	return
 }
+
+// VerifyFactor completes the MFA verification started by Authenticate.
+// On success, the returned user carries the SessionToken.
+// MFA is returned when the factor is rejected.
+func (s *OauthService) VerifyFactor(ctx context.Context, stateToken string, deviceID int, otpToken string) (*AuthenticatedUser, error) {
+	return s.VerifyFactorWithOptions(ctx, stateToken, deviceID, otpToken, nil)
+}
+
+// VerifyFactorOptions configures VerifyFactorWithOptions.
+type VerifyFactorOptions struct {
+	// DoNotNotify prevents a push based factor from sending a new notification,
+	// when checking whether a previous one has been approved.
+	DoNotNotify bool
+}
+
+// VerifyFactorWithOptions verifies a factor like VerifyFactor, configured by opts.
+func (s *OauthService) VerifyFactorWithOptions(ctx context.Context, stateToken string, deviceID int, otpToken string, opts *VerifyFactorOptions) (*AuthenticatedUser, error) {
+	if opts == nil {
+		opts = &VerifyFactorOptions{}
+	}
+
+	return s.verifyFactor(ctx, verifyFactorParams{
+		DeviceID:    deviceID,
+		StateToken:  stateToken,
+		OTPToken:    otpToken,
+		DoNotNotify: opts.DoNotNotify,
+	})
+}
+
+// TriggerFactor sends the push notification of a push based factor, such as OneLogin Protect.
+// The verification must then be completed with PollFactor or WaitForFactor.
+func (s *OauthService) TriggerFactor(ctx context.Context, stateToken string, deviceID int) error {
+	_, err := s.verifyFactor(ctx, verifyFactorParams{
+		DeviceID:   deviceID,
+		StateToken: stateToken,
+	})
+	if err == ErrMFAPending {
+		return nil
+	}
+
+	return err
+}
+
+// PollFactor checks whether the notification sent by TriggerFactor has been approved,
+// without sending a new one.
+// ErrMFAPending is returned while the user hasn't approved the notification yet.
+func (s *OauthService) PollFactor(ctx context.Context, stateToken string, deviceID int) (*AuthenticatedUser, error) {
+	return s.verifyFactor(ctx, verifyFactorParams{
+		DeviceID:    deviceID,
+		StateToken:  stateToken,
+		DoNotNotify: true,
+	})
+}
+
+// WaitForFactor triggers a push based factor and polls it every interval until it gets approved,
+// rejected, or ctx is done. An interval that isn't positive is rejected, without
+// triggering the factor, rather than polling OneLogin in a tight loop.
+func (s *OauthService) WaitForFactor(ctx context.Context, stateToken string, deviceID int, interval time.Duration) (*AuthenticatedUser, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid poll interval %v", interval)
+	}
+
+	if err := s.TriggerFactor(ctx, stateToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		user, err := s.PollFactor(ctx, stateToken, deviceID)
+		if err != ErrMFAPending {
+			return user, err
+		}
+	}
+}
+
+func (s *OauthService) verifyFactor(ctx context.Context, p verifyFactorParams) (*AuthenticatedUser, error) {
+	u := "/api/1/login/verify_factor"
+
+	req, err := s.client.NewRequest("POST", u, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var d []mfaResponse
+	resp, err := s.client.Do(ctx, req, &d)
+	if err != nil {
+		var e *APIError
+		if errors.As(err, &e) && e.StatusCode == http.StatusUnauthorized {
+			return nil, MFA
+		}
+		return nil, err
+	}
+
+	if resp.StatusType == "pending" {
+		return nil, ErrMFAPending
+	}
+
+	if len(d) != 1 || d[0].User == nil || d[0].SessionToken == "" {
+		return nil, MFA
+	}
+
+	user := d[0].User
+	user.SessionToken = d[0].SessionToken
+	user.ExpiresAt = d[0].ExpiresAt.Time
+
+	return user, nil
+}
@@ -0,0 +1,299 @@
+package onelogin_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestClientCredentialsHeader(t *testing.T) {
+	basic := base64.StdEncoding.EncodeToString([]byte(onelogintest.ClientID + ":" + onelogintest.ClientSecret))
+
+	tests := []struct {
+		name string
+		opts []onelogin.ClientOption
+		want string
+	}{
+		{
+			name: "custom scheme",
+			want: "client_id:" + onelogintest.ClientID + ",client_secret:" + onelogintest.ClientSecret,
+		},
+		{
+			name: "basic auth",
+			opts: []onelogin.ClientOption{onelogin.WithBasicAuth()},
+			want: "Basic " + basic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+
+			c := s.Client(tt.opts...)
+			req, err := c.NewRequest("GET", "/api/2/users", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.AddAuthorization(context.Background(), req); err != nil {
+				t.Fatal(err)
+			}
+
+			reqs := s.Requests()
+			if len(reqs) != 1 || reqs[0].Path != "/auth/oauth2/token" {
+				t.Fatalf("got requests %v, want a single token request", reqs)
+			}
+			if got := reqs[0].Header.Get("Authorization"); got != tt.want {
+				t.Errorf("got Authorization %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeClock is a Clock whose time is set by the test.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+}
+
+// handleToken makes s issue tokens created at createdAt, valid for an hour.
+func handleToken(s *onelogintest.Server, createdAt time.Time) {
+	s.HandleJSON("POST", "/auth/oauth2/token", http.StatusOK, map[string]interface{}{
+		"status": map[string]interface{}{"error": false, "code": 200, "type": "success", "message": "Success"},
+		"data": []map[string]interface{}{{
+			"access_token":  onelogintest.AccessToken,
+			"refresh_token": "refresh-token",
+			"account_id":    1,
+			"created_at":    createdAt.Format(time.RFC3339Nano),
+			"expires_in":    3600,
+			"token_type":    "bearer",
+		}},
+	})
+}
+
+// countTokenRequests returns the number of token requests received by s.
+func countTokenRequests(s *onelogintest.Server) int {
+	var n int
+	for _, r := range s.Requests() {
+		if r.Path == "/auth/oauth2/token" {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestTokenExpiry(t *testing.T) {
+	issuedAt := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := issuedAt.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		skew        time.Duration
+		now         time.Time
+		wantRefresh bool
+	}{
+		{name: "just issued", skew: time.Minute, now: issuedAt},
+		{name: "before the skew", skew: time.Minute, now: expiresAt.Add(-2 * time.Minute)},
+		{name: "within the skew", skew: time.Minute, now: expiresAt.Add(-30 * time.Second), wantRefresh: true},
+		{name: "expired", skew: time.Minute, now: expiresAt.Add(time.Second), wantRefresh: true},
+		{name: "expired without skew", now: expiresAt.Add(time.Second), wantRefresh: true},
+		{name: "near expiry without skew", now: expiresAt.Add(-time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			handleToken(s, issuedAt)
+
+			clock := &fakeClock{now: issuedAt}
+			c := s.Client(onelogin.WithClock(clock), onelogin.WithTokenExpirySkew(tt.skew))
+
+			authorize := func() {
+				req, err := c.NewRequest("GET", "/api/2/users", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := c.AddAuthorization(context.Background(), req); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			authorize()
+			clock.Set(tt.now)
+			authorize()
+
+			want := 1
+			if tt.wantRefresh {
+				want = 2
+			}
+			if got := countTokenRequests(s); got != want {
+				t.Errorf("got %d token requests, want %d", got, want)
+			}
+		})
+	}
+}
+
+// memoryTokenStore is a TokenStore keeping the token in memory.
+type memoryTokenStore struct {
+	token *onelogin.Token
+}
+
+func (s *memoryTokenStore) Load() (*onelogin.Token, error) {
+	return s.token, nil
+}
+
+func (s *memoryTokenStore) Save(t *onelogin.Token) error {
+	s.token = t
+	return nil
+}
+
+func TestTokenNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "number", value: `3600`, want: 3600},
+		{name: "string", value: `"3600"`, want: 3600},
+		{name: "float", value: `3600.0`, want: 3600},
+		{name: "null", value: `null`, want: 0},
+		{name: "invalid", value: `"an hour"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			s.HandleFunc("POST", "/auth/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"access_token":%q,"account_id":%s,"expires_in":%s,"token_type":"bearer"}`,
+					onelogintest.AccessToken, tt.value, tt.value)
+			})
+
+			store := &memoryTokenStore{}
+			c := s.Client(onelogin.WithTokenStore(store))
+
+			id, err := c.AccountID(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if id != tt.want {
+				t.Errorf("got account id %d, want %d", id, tt.want)
+			}
+			if store.token == nil || store.token.ExpiresIn != int64(tt.want) {
+				t.Errorf("got token %+v, want it to expire in %d seconds", store.token, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForFactorInvalidInterval(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := c.Oauth.WaitForFactor(context.Background(), "state-token", 1, interval); err == nil {
+			t.Errorf("got no error for the interval %v, want one", interval)
+		}
+	}
+
+	if reqs := s.Requests(); len(reqs) != 0 {
+		t.Errorf("got %d requests, want none", len(reqs))
+	}
+}
+
+func TestTokenErrorCause(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		message string
+		want    []error
+		notWant []error
+	}{
+		{
+			name:    "suspended",
+			status:  http.StatusForbidden,
+			message: "Account suspended",
+			want:    []error{onelogin.ErrAccountSuspended, onelogin.ErrForbidden},
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			message: "Forbidden",
+			want:    []error{onelogin.ErrForbidden},
+			notWant: []error{onelogin.ErrAccountSuspended},
+		},
+		{
+			name:    "invalid client",
+			status:  http.StatusUnauthorized,
+			message: "Authentication Failure",
+			want:    []error{onelogin.ErrInvalidClient},
+			notWant: []error{onelogin.ErrAccountSuspended},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			s.HandleJSON("POST", "/auth/oauth2/token", tt.status, map[string]interface{}{
+				"status": map[string]interface{}{"error": true, "code": tt.status, "type": "error", "message": tt.message},
+			})
+
+			c := s.Client()
+			req, err := c.NewRequest("GET", "/api/2/users", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.AddAuthorization(context.Background(), req)
+
+			var e *onelogin.TokenError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want a *TokenError", err)
+			}
+			for _, target := range tt.want {
+				if !errors.Is(err, target) {
+					t.Errorf("got error %v, want it to match %v", err, target)
+				}
+			}
+			for _, target := range tt.notWant {
+				if errors.Is(err, target) {
+					t.Errorf("got error %v, want it not to match %v", err, target)
+				}
+			}
+		})
+	}
+}
@@ -4,21 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
-	"log"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
 
 const (
-	baseURL = "https://api.%s.onelogin.com/"
+	// Version of the library, sent in the default User-Agent.
+	Version = "0.1.0"
+
+	baseURL          = "https://api.%s.onelogin.com/"
+	defaultUserAgent = "onelogin-go/" + Version
+
+	defaultTokenExpirySkew = time.Minute
+	defaultTimeout         = 30 * time.Second
+
+	defaultMaxResponseBytes = 64 << 20
 )
 
 type service struct {
@@ -34,42 +45,181 @@ type Client struct {
 	clientID     string
 	clientSecret string
 	subdomain    string
+	basicAuth    bool
+	grantType    string
+	grantParams  GrantParams
 
 	// User agent used when communicating with the OneLogin api.
+	// It defaults to onelogin-go/<Version>.
 	UserAgent string
 
+	// MaxRetries is the number of times a request failing with a 429 or 5xx
+	// (429 only for POST) status code is retried. Zero disables the retries.
+	MaxRetries int
+	// RetryBaseDelay is the initial delay of the exponential backoff between
+	// two retries, used when OneLogin doesn't provide a Retry-After header.
+	RetryBaseDelay time.Duration
+
+	// MaxResponseBytes is the size above which a response body is rejected with
+	// ErrResponseTooLarge, protecting from memory exhaustion. It defaults to 64 MiB,
+	// and zero disables it.
+	MaxResponseBytes int64
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
-	oauthToken *oauthToken
+	// oauthToken is only read and replaced while holding the lock of the client.
+	oauthToken      *oauthToken
+	tokenStore      TokenStore
+	tokenExpirySkew time.Duration
+	clock           Clock
+
+	timeout       time.Duration
+	revokeOnClose bool
+	language      string
+	headers       http.Header
+
+	usersAPIVersion int
+
+	logger Logger
+
+	rateLimitMu sync.Mutex
+	rateLimit   *RateLimit
 
 	Oauth *OauthService
 	User  *UserService
 	Role  *RoleService
 	Group *GroupService
-	// SAMLService  *SAMLService
-	// EventService *EventService
+
+	Users      *UsersService
+	Roles      *RolesService
+	Apps       *AppsService
+	Events     *EventsService
+	Groups     *GroupsService
+	Mappings   *MappingsService
+	Privileges *PrivilegesService
+	Factors    *FactorsService
+	SmartHooks *SmartHooksService
+	Risk       *RiskService
 
 	sync.Mutex
 }
 
 // New returns a new OneLogin client.
+// The shard isn't validated, use NewClient with WithRegion for that.
 func New(clientID, clientSecret, shard, subdomain string) *Client {
+	c := newClient(clientID, clientSecret, subdomain)
+	c.BaseURL, _ = url.Parse(buildURL(baseURL, shard))
+
+	return c
+}
+
+// NewClient returns a new OneLogin client configured with opts.
+// The client targets the RegionUS data center unless WithRegion is provided.
+func NewClient(clientID, clientSecret, subdomain string, opts ...ClientOption) (*Client, error) {
+	c := newClient(clientID, clientSecret, subdomain)
+	c.BaseURL, _ = url.Parse(buildURL(baseURL, RegionUS))
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func newClient(clientID, clientSecret, subdomain string) *Client {
 	c := &Client{
 		client:       http.DefaultClient,
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		subdomain:    subdomain,
+		grantType:    GrantClientCredentials,
+
+		tokenExpirySkew: defaultTokenExpirySkew,
+		timeout:         defaultTimeout,
+		usersAPIVersion: 2,
+
+		UserAgent:      defaultUserAgent,
+		MaxRetries:     defaultMaxRetries,
+		RetryBaseDelay: defaultRetryBaseDelay,
+
+		MaxResponseBytes: defaultMaxResponseBytes,
 	}
 	c.common.client = c
-	c.BaseURL, _ = url.Parse(buildURL(baseURL, shard))
 	c.Oauth = (*OauthService)(&c.common)
 	c.User = (*UserService)(&c.common)
 	c.Role = (*RoleService)(&c.common)
 	c.Group = (*GroupService)(&c.common)
+	c.Users = (*UsersService)(&c.common)
+	c.Roles = (*RolesService)(&c.common)
+	c.Apps = (*AppsService)(&c.common)
+	c.Events = (*EventsService)(&c.common)
+	c.Groups = (*GroupsService)(&c.common)
+	c.Mappings = (*MappingsService)(&c.common)
+	c.Privileges = (*PrivilegesService)(&c.common)
+	c.Factors = (*FactorsService)(&c.common)
+	c.SmartHooks = (*SmartHooksService)(&c.common)
+	c.Risk = (*RiskService)(&c.common)
 
 	return c
 }
 
+// Close releases the resources of the client: it revokes the current token when
+// configured with WithRevokeOnClose, and closes the idle connections.
+// The client remains usable after Close, issuing a new token and opening new
+// connections as needed.
+func (c *Client) Close() error {
+	var err error
+	if c.revokeOnClose {
+		err = c.Oauth.RevokeToken(context.Background())
+	}
+
+	c.client.CloseIdleConnections()
+
+	return err
+}
+
+// now returns the current time, as told by the clock of the client (if any).
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock.Now()
+	}
+
+	return time.Now()
+}
+
+// SetCredentials replaces the client credentials used to issue the tokens, e.g.
+// after rotating the client secret. The current token remains in use until it
+// expires: call Reauthenticate to replace it right away.
+func (c *Client) SetCredentials(clientID, clientSecret string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.clientID = clientID
+	c.clientSecret = clientSecret
+}
+
+// Reauthenticate drops the current token and issues a new one with the client
+// credentials, saving it into the TokenStore (if any).
+// The current token is left in use when the new one can't be issued.
+func (c *Client) Reauthenticate(ctx context.Context) error {
+	c.Lock()
+	defer c.Unlock()
+
+	token, err := c.Oauth.getToken(ctx)
+	if err != nil {
+		return err
+	}
+	c.oauthToken = token
+
+	if c.tokenStore != nil {
+		return c.tokenStore.Save(token.token())
+	}
+
+	return nil
+}
+
 type urlQuery struct {
 	AfterCursor string `url:"after_cursor,omitempty"`
 }
@@ -78,7 +228,7 @@ type urlQuery struct {
 // must be a struct whose fields may contain "url" tags.
 func addOptions(s string, opt interface{}) (string, error) {
 	v := reflect.ValueOf(opt)
-	if v.Kind() == reflect.Ptr && v.IsNil() {
+	if opt == nil || v.Kind() == reflect.Ptr && v.IsNil() {
 		return s, nil
 	}
 
@@ -97,44 +247,122 @@ func addOptions(s string, opt interface{}) (string, error) {
 }
 
 // AddAuthorization injects the Authorization header to the request.
-// If the client doesn't has an oauthToken, a new token is issed.
-// If the token is expired, it is automatically refreshed.
+// If the client doesn't has an oauthToken, it is loaded from the TokenStore
+// (if any), or a new token is issed.
+// If the token is expired, it is automatically refreshed, or issued again when
+// it has no refresh token. A failed refresh returns a *TokenRefreshError.
+// The client lock ensures concurrent requests only refresh the token once.
+// Issued and refreshed tokens are saved into the TokenStore.
 func (c *Client) AddAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("bearer:%s", token.AccessToken))
+
+	return nil
+}
+
+// validToken returns the token of the client, loading, issuing or refreshing it
+// as documented by AddAuthorization.
+func (c *Client) validToken(ctx context.Context) (*oauthToken, error) {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.oauthToken == nil && c.tokenStore != nil {
+		t, err := c.tokenStore.Load()
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			c.oauthToken = newOauthToken(c, t)
+		}
+	}
+
+	changed := false
 	if c.oauthToken == nil {
 		var err error
 
 		c.oauthToken, err = c.Oauth.getToken(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		changed = true
 	}
 
 	if c.oauthToken.isExpired() {
-		if err := c.oauthToken.refresh(ctx); err != nil {
-			return err
+		if c.oauthToken.refreshToken == "" && c.oauthToken.provided {
+			// The client may not even hold the client secret to issue a new one.
+			return nil, ErrAccessTokenExpired
+		} else if c.oauthToken.refreshToken == "" {
+			token, err := c.Oauth.getToken(ctx)
+			if err != nil {
+				return nil, err
+			}
+			c.oauthToken = token
+		} else {
+			token, err := c.oauthToken.refresh(ctx)
+			if err != nil {
+				return nil, &TokenRefreshError{Err: err}
+			}
+			c.oauthToken = token
 		}
+		changed = true
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("bearer:%s", c.oauthToken.AccessToken))
+	if changed && c.tokenStore != nil {
+		if err := c.tokenStore.Save(c.oauthToken.token()); err != nil {
+			return nil, err
+		}
+	}
 
-	return nil
+	return c.oauthToken, nil
+}
+
+// AccountID returns the id of the OneLogin account the credentials of the client
+// belong to, as reported by its access token, which gets issued if needed.
+// OneLogin has no endpoint returning the details of the account: the id is zero
+// when the token was given with WithAccessToken.
+func (c *Client) AccountID(ctx context.Context) (int, error) {
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return token.AccountID, nil
 }
 
 // Do sends an API request and returns the API response. The API response is
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred. If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
-// first decode it.
+// first decode it. A body larger than the MaxResponseBytes of the client fails
+// with ErrResponseTooLarge.
+// The data of the v1 responses is unwrapped from their status envelope, so v
+// holds the same kind of value for the v1 and v2 endpoints.
 //
 // The provided ctx must be non-nil. If it is canceled or times out,
-// ctx.Err() will be returned.
+// ctx.Err() will be returned. The headers added to ctx with ContextWithHeader
+// are set on the request.
+//
+// Requests failing with a 429 or 5xx status code are retried up to MaxRetries times.
+// As OneLogin doesn't support idempotency keys, the POST requests are only retried
+// on a 429: they may have created their resource when failing with a 5xx.
+//
+// When ctx has no deadline, the request (retries included) is aborted after the
+// timeout of the client, 30 seconds by default.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
-	req = req.WithContext(ctx)
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req = setContextHeaders(req.WithContext(ctx))
 
-	resp, err := c.client.Do(req)
+	start := time.Now()
+	resp, err := c.send(ctx, req)
+	c.logRequest(ctx, req, resp, err, time.Since(start))
 	if err != nil {
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
@@ -152,7 +380,15 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 		_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
 		_ = resp.Body.Close()
 	}()
+	if c.MaxResponseBytes > 0 {
+		resp.Body = &maxBytesReader{ReadCloser: resp.Body, n: c.MaxResponseBytes}
+	}
 	response := newResponse(resp)
+	if response.RateLimit != nil {
+		c.rateLimitMu.Lock()
+		c.rateLimit = response.RateLimit
+		c.rateLimitMu.Unlock()
+	}
 
 	err = CheckResponse(resp)
 	if err != nil {
@@ -162,28 +398,17 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	}
 
 	if v != nil {
-
-		respData, err := httputil.DumpResponse(resp, true)
-		if err == nil {
-			log.Printf("[DEBUG] "+logRespMsg, req.URL.String(), string(respData))
-		} else {
-			log.Printf("[ERROR] %s API Response error: %#v", req.URL.String(), err)
-		}
-
 		if w, ok := v.(io.Writer); ok {
-			io.Copy(w, resp.Body)
+			_, err = io.Copy(w, resp.Body)
 		} else {
-			var m responseMessage
-			err = json.NewDecoder(resp.Body).Decode(&m)
+			var raw json.RawMessage
+			err = json.NewDecoder(resp.Body).Decode(&raw)
 			if err == io.EOF {
 				err = nil // ignore EOF errors caused by empty response body.
 			}
 
-			err = json.Unmarshal(m.Data, v)
-
-			if m.Pagination != nil {
-				response.PaginationAfterCursor = m.Pagination.AfterCursor
-				response.PaginationBeforeCursor = m.Pagination.BeforeCursor
+			if err == nil && len(raw) > 0 {
+				err = decodeBody(raw, v, response)
 			}
 		}
 	}
@@ -191,18 +416,82 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	return response, err
 }
 
+// decodeBody decodes the data of a response body into v.
+// The v1 API wraps the data into an envelope along a status and the pagination,
+// whereas the v2 API returns the data as is.
+func decodeBody(raw json.RawMessage, v interface{}, response *Response) error {
+	var envelope struct {
+		Status json.RawMessage `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil || !bytes.HasPrefix(bytes.TrimSpace(envelope.Status), []byte("{")) {
+		// Either an array, or a v2 object whose status (if any) is not the envelope one.
+		return json.Unmarshal(raw, v)
+	}
+
+	var m responseMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return err
+	}
+
+	response.StatusType = m.Status.Type
+	if m.Pagination != nil {
+		response.PaginationAfterCursor = m.Pagination.AfterCursor
+		response.PaginationBeforeCursor = m.Pagination.BeforeCursor
+	}
+
+	// Some responses, such as a pending MFA verification, only carry a status.
+	if len(m.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(m.Data, v)
+}
+
+// newResponse wraps resp, reading the rate limit and v2 pagination cursors from its headers.
+// The v1 cursors are read from the body by decodeBody.
 func newResponse(resp *http.Response) *Response {
-	return &Response{Response: resp}
+	response := &Response{
+		Response:  resp,
+		RateLimit: parseRateLimit(resp.Header),
+	}
+	if c := resp.Header.Get("After-Cursor"); c != "" {
+		response.PaginationAfterCursor = &c
+	}
+	if c := resp.Header.Get("Before-Cursor"); c != "" {
+		response.PaginationBeforeCursor = &c
+	}
+
+	return response
 }
 
 // NewRequest instantiate a new http.Request from a method, url and body.
 // The body (if provided) is automatically Marshalled into JSON.
+//
+// A relative urlStr, such as "/api/2/users", is resolved against the BaseURL of
+// the client. Together with AddAuthorization and Do, it calls the endpoints the
+// services don't support yet, with the same authentication, retries and error
+// handling:
+//
+//	req, err := c.NewRequest("GET", "/api/2/branding/brands", nil)
+//	if err != nil {
+//		return err
+//	}
+//	if err := c.AddAuthorization(ctx, req); err != nil {
+//		return err
+//	}
+//	var brands []map[string]interface{}
+//	_, err = c.Do(ctx, req, &brands)
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
 	}
 
+	if !rel.IsAbs() && rel.Host == "" {
+		// Resolve the paths relative to the one of the BaseURL, e.g. a gateway.
+		rel.Path = strings.TrimPrefix(rel.Path, "/")
+		rel.RawPath = strings.TrimPrefix(rel.RawPath, "/")
+	}
 	u := c.BaseURL.ResolveReference(rel)
 
 	var buf io.ReadWriter
@@ -219,6 +508,11 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		return nil, err
 	}
 
+	// The headers set by the client take precedence over the default ones.
+	for k, v := range c.headers {
+		req.Header[k] = v
+	}
+
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -227,10 +521,18 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
 
 	return req, nil
 }
 
+// idResponse is the body returned by the v2 endpoints creating or updating a resource.
+type idResponse struct {
+	ID int64 `json:"id"`
+}
+
 type responseMessage struct {
 	Status struct {
 		Code    int64  `json:"code"`
@@ -246,70 +548,223 @@ type responseMessage struct {
 
 // CheckResponse checks the *http.Response.
 // HTTP status codes ranging from 200 to 299 are considered are successes.
-// Otherwise an error happen, and the error gets unmarshalled and returned into the error.
+// Otherwise an error happen, and the error gets unmarshalled and returned as an *APIError.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 
-	errorResponse := &ErrorResponse{Response: r}
+	apiError := &APIError{Response: r, StatusCode: r.StatusCode}
 	data, err := ioutil.ReadAll(r.Body)
 	if err == nil && data != nil {
-		var m responseMessage
+		var m errorMessage
 		_ = json.Unmarshal(data, &m)
-		errorResponse.Code = m.Status.Code
-		errorResponse.Type = m.Status.Type
-		errorResponse.Message = m.Status.Message
+		if m.Status != nil {
+			apiError.Code = m.Status.Code
+			apiError.Type = m.Status.Type
+			apiError.Message = m.Status.Message
+		} else {
+			apiError.Code = m.StatusCode
+			apiError.Type = m.Name
+			apiError.Message = m.Message
+			apiError.Errors = m.Errors
+		}
 	}
 
 	// TODO: handle the different errors here, such as MFA, Rate limit, etc...
-	return errorResponse
+	return apiError
+}
+
+// errorMessage is an error body returned by either the v1 or the v2 API.
+type errorMessage struct {
+	// v1
+	Status *struct {
+		Code    int64  `json:"code"`
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"status"`
+
+	// v2
+	StatusCode int64         `json:"statusCode"`
+	Name       string        `json:"name"`
+	Message    string        `json:"message"`
+	Errors     []*FieldError `json:"errors"`
 }
 
 // Response embeds a *http.Response as well as some Paginations values.
+// It is returned by Do even when the request fails with an *APIError, so the
+// status code and the headers (Header.Get("X-Request-Id"), ...) can always be
+// inspected. The cursors and the rate limit are parsed from the headers by Do.
+// The body has already been consumed and closed.
 type Response struct {
 	*http.Response
 
 	PaginationAfterCursor  *string
 	PaginationBeforeCursor *string
+
+	// StatusType is the type of the status OneLogin returned along the data, such as "success" or "pending".
+	StatusType string
+
+	// RateLimit is read from the X-RateLimit-* headers, nil when they are absent.
+	RateLimit *RateLimit
 }
 
-// An ErrorResponse reports an error caused by an API request.
+// An APIError reports an error caused by an API request.
 // Onelogin always returns Code, Type and a Message associated to the error.
 // Example:
-// {
-//     "status": {
-//         "error": true,
-//         "code": 400,
-//         "type": "bad request",
-//         "message": "Content Type is not specified or specified incorrectly.
-//                     Content-Type header must be set to application/json"
-//     }
-// }
-type ErrorResponse struct {
-	Response *http.Response // HTTP response that caused this error
+//
+//	{
+//	    "status": {
+//	        "error": true,
+//	        "code": 400,
+//	        "type": "bad request",
+//	        "message": "Content Type is not specified or specified incorrectly.
+//	                    Content-Type header must be set to application/json"
+//	    }
+//	}
+//
+// The v2 API returns the same information as statusCode, name and message,
+// which are respectively stored into Code, Type and Message.
+type APIError struct {
+	Response   *http.Response // HTTP response that caused this error
+	StatusCode int            // HTTP status code of the response
 
 	Code    int64
 	Type    string
 	Message string
+
+	// Errors lists the invalid fields of a v2 validation error (422).
+	Errors []*FieldError
+}
+
+// A FieldError describes why a field of a request is invalid.
+type FieldError struct {
+	Field    string     `json:"field"`
+	Messages stringList `json:"message"`
+}
+
+// stringList decodes either a JSON string or an array of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return err
+	}
+	*l = ss
+
+	return nil
+}
+
+// Sentinel errors matched by the APIError of the responses with the matching status
+// code, with errors.Is.
+var (
+	// ErrNotFound is matched by the APIError of a 404 response.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is matched by the APIError of a 401 response.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is matched by the APIError of a 403 response.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Is makes errors.Is match the error with the sentinel error of its status code.
+func (r *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return r.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return r.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return r.StatusCode == http.StatusForbidden
+	}
+
+	return false
+}
+
+// ErrResponseTooLarge is returned when a response body exceeds the MaxResponseBytes
+// of the client.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// maxBytesReader fails with ErrResponseTooLarge once more than n bytes are read,
+// and on every read after that.
+type maxBytesReader struct {
+	io.ReadCloser
+	n        int64
+	exceeded bool
 }
 
-func (r *ErrorResponse) Error() string {
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, ErrResponseTooLarge
+	}
+
+	if int64(len(p))-1 > r.n {
+		// Read one more byte than allowed to detect the bodies too large.
+		p = p[:r.n+1]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) > r.n {
+		n, r.n = int(r.n), 0
+		r.exceeded = true
+		return n, ErrResponseTooLarge
+	}
+	r.n -= int64(n)
+
+	return n, err
+}
+
+// flexInt decodes an integer sent either as a JSON number, possibly with a
+// fractional part, or as a string, as the endpoints disagree on the format.
+type flexInt int64
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*n = flexInt(i)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+	*n = flexInt(f)
+
+	return nil
+}
+
+// ErrorResponse is the former name of APIError.
+//
+// Deprecated: use APIError.
+type ErrorResponse = APIError
+
+func (r *APIError) Error() string {
 	return fmt.Sprintf("%v %v: OneLogin responsed with code %d, type %v and message %v",
 		r.Response.Request.Method, r.Response.Request.URL,
-		r.Response.StatusCode, r.Type, r.Message)
+		r.StatusCode, r.Type, r.Message)
 }
 
+// String returns a pointer to v, to set the optional fields of the update requests.
+func String(v string) *string { return &v }
+
+// Int64 returns a pointer to v, to set the optional fields of the update requests.
+func Int64(v int64) *int64 { return &v }
+
+// Bool returns a pointer to v, to set the optional fields of the update requests.
+func Bool(v bool) *bool { return &v }
+
 func buildURL(baseURL string, args ...interface{}) string {
 	return fmt.Sprintf(baseURL, args...)
 }
-
-const logReqMsg = `%s API Request Details:
----[ REQUEST ]---------------------------------------
-%s
------------------------------------------------------`
-
-const logRespMsg = `%s API Response Details:
----[ RESPONSE ]--------------------------------------
-%s
------------------------------------------------------`
@@ -0,0 +1,76 @@
+package onelogin_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestAddAuthorizationConcurrent(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+
+	const n = 50
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			req, err := c.NewRequest("GET", "/api/2/users", nil)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = c.AddAuthorization(context.Background(), req)
+			if errs[i] == nil && req.Header.Get("Authorization") != "bearer:"+onelogintest.AccessToken {
+				t.Errorf("got Authorization %q", req.Header.Get("Authorization"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countTokenRequests(s); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	const limit = 101
+	body := `{"id":1,"email":"` + strings.Repeat("a", limit+1-len(`{"id":1,"email":""}`)) + `"}`
+	s.HandleFunc("GET", "/api/2/users/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	})
+
+	c := s.Client()
+	// Issue the token first, its response being larger than the limit.
+	if _, err := c.AccountID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	c.MaxResponseBytes = limit
+
+	_, err := c.Users.Get(context.Background(), 1)
+	if !errors.Is(err, onelogin.ErrResponseTooLarge) {
+		t.Errorf("got error %v for a %d bytes body, want ErrResponseTooLarge", err, len(body))
+	}
+}
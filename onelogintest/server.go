@@ -0,0 +1,212 @@
+// Package onelogintest provides a fake OneLogin API, to test code using the
+// onelogin package without reaching OneLogin.
+//
+//	s := onelogintest.NewServer()
+//	defer s.Close()
+//	s.HandleJSON("GET", "/api/2/users/1", http.StatusOK, map[string]interface{}{"id": 1})
+//
+//	c := s.Client()
+//	user, err := c.Users.Get(context.Background(), 1)
+package onelogintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/drewsonne/onelogin"
+)
+
+// Credentials accepted by the token endpoint of the fake server.
+const (
+	ClientID     = "onelogintest-client-id"
+	ClientSecret = "onelogintest-client-secret"
+	Subdomain    = "onelogintest"
+	AccessToken  = "onelogintest-access-token"
+)
+
+// A Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake OneLogin API, serving the token endpoints and the
+// responses registered with Handle and HandleJSON.
+// Unregistered endpoints respond with a 404.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+	requests []*Request
+}
+
+// NewServer starts a fake OneLogin API. It must be closed once done.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]http.Handler),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	s.HandleFunc("POST", "/auth/oauth2/token", s.serveToken)
+	s.HandleJSON("POST", "/auth/oauth2/revoke", http.StatusOK, v1Envelope(nil))
+	s.HandleJSON("GET", "/auth/rate_limit", http.StatusOK, v1Envelope(map[string]int{
+		"X-RateLimit-Limit":     5000,
+		"X-RateLimit-Remaining": 5000,
+		"X-RateLimit-Reset":     3600,
+	}))
+
+	return s
+}
+
+// Client returns a client sending its requests to the server, with the retries
+// disabled. It panics if one of the opts fails.
+func (s *Server) Client(opts ...onelogin.ClientOption) *onelogin.Client {
+	opts = append([]onelogin.ClientOption{
+		onelogin.WithBaseURL(s.URL),
+		onelogin.WithHTTPClient(s.Server.Client()),
+		onelogin.WithoutRetries(),
+	}, opts...)
+
+	c, err := onelogin.NewClient(ClientID, ClientSecret, Subdomain, opts...)
+	if err != nil {
+		panic("onelogintest: " + err.Error())
+	}
+
+	return c
+}
+
+// Handle registers the handler serving the requests with the method and path,
+// replacing the previous one.
+func (s *Server) Handle(method, path string, h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method+" "+path] = h
+}
+
+// HandleFunc registers the handler function serving the requests with the method and path.
+func (s *Server) HandleFunc(method, path string, h func(http.ResponseWriter, *http.Request)) {
+	s.Handle(method, path, http.HandlerFunc(h))
+}
+
+// HandleJSON makes the server respond to the requests with the method and path
+// with status and body encoded as JSON.
+func (s *Server) HandleJSON(method, path string, status int, body interface{}) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, status, body)
+	})
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Request(nil), s.requests...)
+}
+
+// Reset forgets the requests received so far.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	h, ok := s.handlers[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"statusCode": http.StatusNotFound,
+			"name":       "NotFound",
+			"message":    "onelogintest: no handler for " + r.Method + " " + r.URL.Path,
+		})
+		return
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	h.ServeHTTP(w, r)
+}
+
+// serveToken issues a token when the client credentials are valid.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	valid := ok && id == ClientID && secret == ClientSecret
+	if !valid {
+		valid = r.Header.Get("Authorization") == "client_id:"+ClientID+",client_secret:"+ClientSecret
+	}
+	if !valid {
+		// Refreshing a token doesn't need the client credentials.
+		var p struct {
+			GrantType string `json:"grant_type"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		valid = json.Unmarshal(body, &p) == nil && p.GrantType == "refresh_token"
+	}
+
+	if !valid {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"status": map[string]interface{}{
+				"error":   true,
+				"code":    http.StatusUnauthorized,
+				"type":    "Unauthorized",
+				"message": "Authentication Failure",
+			},
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v1Envelope([]map[string]interface{}{{
+		"access_token":  AccessToken,
+		"refresh_token": "onelogintest-refresh-token",
+		"account_id":    1,
+		"created_at":    time.Now().UTC().Format(time.RFC3339Nano),
+		"expires_in":    36000,
+		"token_type":    "bearer",
+	}}))
+}
+
+// v1Envelope wraps data into the envelope of the v1 API responses.
+func v1Envelope(data interface{}) map[string]interface{} {
+	m := map[string]interface{}{
+		"status": map[string]interface{}{
+			"error":   false,
+			"code":    http.StatusOK,
+			"type":    "success",
+			"message": "Success",
+		},
+	}
+	if data != nil {
+		m["data"] = data
+	}
+
+	return m
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,367 @@
+package onelogin
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Regions (also known as shards) hosting OneLogin accounts.
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+)
+
+// A ClientOption configures a Client created with NewClient.
+type ClientOption func(*Client) error
+
+// WithRegion makes the client target the API of the given region.
+// An error is returned if the region is unknown.
+func WithRegion(region string) ClientOption {
+	return func(c *Client) error {
+		switch region {
+		case RegionUS, RegionEU:
+		default:
+			return fmt.Errorf("onelogin: unknown region %q", region)
+		}
+
+		u, err := url.Parse(buildURL(baseURL, region))
+		if err != nil {
+			return err
+		}
+		c.BaseURL = u
+
+		return nil
+	}
+}
+
+// WithBaseURL makes the client target the API at baseURL instead of the one of
+// its region, e.g. a mock server or a gateway in front of OneLogin.
+// An error is returned if baseURL isn't an absolute URL.
+// The paths of the requests are resolved relative to the path of baseURL.
+// Like WithRegion, which it overrides, the last of the two options wins.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("onelogin: invalid base URL %q: %w", baseURL, err)
+		}
+		if !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("onelogin: base URL %q isn't absolute", baseURL)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+			if u.RawPath != "" {
+				u.RawPath += "/"
+			}
+		}
+		c.BaseURL = u
+
+		return nil
+	}
+}
+
+// WithBasicAuth sends the client credentials using the standard HTTP Basic
+// scheme when issuing tokens, instead of OneLogin's custom scheme.
+func WithBasicAuth() ClientOption {
+	return func(c *Client) error {
+		c.basicAuth = true
+		return nil
+	}
+}
+
+// WithGrantType selects how the client issues its tokens, GrantClientCredentials
+// by default. An error is returned if the grant type is unknown, or if params
+// lacks its required parameters. The client credentials are sent with every grant.
+func WithGrantType(grantType string, params GrantParams) ClientOption {
+	return func(c *Client) error {
+		switch grantType {
+		case GrantClientCredentials:
+			params = GrantParams{}
+		case GrantPassword:
+			if params.Username == "" || params.Password == "" {
+				return fmt.Errorf("onelogin: the %s grant requires a username and a password", grantType)
+			}
+			params.RefreshToken = ""
+		case GrantRefreshToken:
+			if params.RefreshToken == "" {
+				return fmt.Errorf("onelogin: the %s grant requires a refresh token", grantType)
+			}
+			params.Username, params.Password = "", ""
+		default:
+			return fmt.Errorf("onelogin: unknown grant type %q", grantType)
+		}
+		c.grantType = grantType
+		c.grantParams = params
+		return nil
+	}
+}
+
+// WithRetries configures how requests failing with a 429 or 5xx status code are retried.
+// The POST requests are only retried on a 429, not to create duplicate resources.
+func WithRetries(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) error {
+		if maxRetries < 0 || baseDelay < 0 {
+			return fmt.Errorf("onelogin: invalid retries %d with base delay %v", maxRetries, baseDelay)
+		}
+		c.MaxRetries = maxRetries
+		c.RetryBaseDelay = baseDelay
+		return nil
+	}
+}
+
+// WithoutRetries disables the retries of the failed requests.
+func WithoutRetries() ClientOption {
+	return WithRetries(0, 0)
+}
+
+// WithHTTPClient makes the client send its requests with hc instead of
+// http.DefaultClient, to control the transport, proxy or TLS settings.
+//
+// A request is aborted as soon as either hc.Timeout elapses or the context
+// passed to the service methods is done, whichever comes first.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("onelogin: nil http client")
+		}
+		c.client = hc
+		return nil
+	}
+}
+
+// WithRoundTripper makes the client send its requests through rt, e.g. a chain of
+// middlewares ending with http.DefaultTransport, keeping the other settings of the
+// HTTP client. It applies to the HTTP client configured by the previous options:
+// a subsequent WithHTTPClient replaces it.
+//
+// The retries of the client happen above rt: rt sees every attempt of a request,
+// and its own retries, if any, add up with them (see WithoutRetries).
+func WithRoundTripper(rt http.RoundTripper) ClientOption {
+	return func(c *Client) error {
+		if rt == nil {
+			return fmt.Errorf("onelogin: nil round tripper")
+		}
+		// Copy the HTTP client, which may be shared, e.g. http.DefaultClient.
+		hc := *c.client
+		hc.Transport = rt
+		c.client = &hc
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the TLS certificates of
+// the server when skip is true, e.g. for a sandbox behind a proxy with a self
+// signed certificate.
+//
+// WARNING: never use it in production. Anyone on the network path can then
+// impersonate OneLogin, and steal the client credentials and tokens.
+//
+// It applies to the transport of the HTTP client configured by the previous
+// options, which must be an *http.Transport.
+func WithInsecureSkipVerify(skip bool) ClientOption {
+	return func(c *Client) error {
+		if !skip {
+			return nil
+		}
+
+		var t *http.Transport
+		switch rt := c.client.Transport.(type) {
+		case nil:
+			t = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			t = rt.Clone()
+		default:
+			return fmt.Errorf("onelogin: can't skip the TLS verification of a %T transport", rt)
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+
+		// Copy the HTTP client, which may be shared, e.g. http.DefaultClient.
+		hc := *c.client
+		hc.Transport = t
+		c.client = &hc
+		return nil
+	}
+}
+
+// WithUserAgent overrides the default User-Agent header, which identifies
+// the library, for example to append the name of the calling service.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = ua
+		return nil
+	}
+}
+
+// WithUsersAPIVersion makes the UsersService target the given version (1 or 2)
+// of the users API, 2 by default.
+// Only List, ListPage, ListAll, Pager, Get, Create, Update and Delete follow the version;
+// the other methods keep their endpoint. The v1 API ignores the RoleIDs and the
+// CustomAttributes of UserCreate, and doesn't support the Fields of the options.
+func WithUsersAPIVersion(version int) ClientOption {
+	return func(c *Client) error {
+		if version != 1 && version != 2 {
+			return fmt.Errorf("onelogin: unknown users API version %d", version)
+		}
+		c.usersAPIVersion = version
+		return nil
+	}
+}
+
+// reservedHeaders are set by the client itself.
+var reservedHeaders = map[string]bool{
+	"Authorization":   true,
+	"Content-Type":    true,
+	"Accept":          true,
+	"User-Agent":      true,
+	"Accept-Language": true,
+}
+
+// WithDefaultHeaders adds the headers to all the requests, e.g. a correlation id.
+// An error is returned for the Authorization, Content-Type, Accept, User-Agent and
+// Accept-Language headers, which are set by the client: use WithUserAgent and
+// WithLanguage for the last two. The default headers are overridden by the headers added
+// to the context of a request with ContextWithHeader.
+func WithDefaultHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) error {
+		for k := range headers {
+			if k := http.CanonicalHeaderKey(k); reservedHeaders[k] {
+				return fmt.Errorf("onelogin: the %s header can't be a default header", k)
+			}
+		}
+
+		if c.headers == nil {
+			c.headers = make(http.Header, len(headers))
+		}
+		for k, v := range headers {
+			c.headers.Set(k, v)
+		}
+		return nil
+	}
+}
+
+// WithLanguage sets the Accept-Language header of the requests, so OneLogin
+// localizes its messages, e.g. the validation errors of the passwords.
+// It can be overridden for a request with ContextWithHeader.
+func WithLanguage(lang string) ClientOption {
+	return func(c *Client) error {
+		c.language = lang
+		return nil
+	}
+}
+
+// WithTokenStore makes the client reuse the token persisted into store, and
+// persist the tokens it issues or refreshes, so they survive process restarts.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) error {
+		c.tokenStore = store
+		return nil
+	}
+}
+
+// WithLogger makes the client report every request it sends to logger.
+// Secrets are redacted from the reported requests.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithTokenExpirySkew sets how long before its expiration a token gets refreshed.
+// It defaults to one minute.
+func WithTokenExpirySkew(skew time.Duration) ClientOption {
+	return func(c *Client) error {
+		if skew < 0 {
+			return fmt.Errorf("onelogin: negative token expiry skew %v", skew)
+		}
+		c.tokenExpirySkew = skew
+		return nil
+	}
+}
+
+// A Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// WithClock makes the client tell the expiration of its tokens with clock instead
+// of the system time, e.g. to test the refresh of the expired tokens.
+// The timeouts and the delays between the retries still use the system time.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) error {
+		if clock == nil {
+			return fmt.Errorf("onelogin: nil clock")
+		}
+		c.clock = clock
+		return nil
+	}
+}
+
+// WithTimeout sets the deadline applied to the requests whose context has none.
+// It never overrides the deadline set by the caller. It defaults to 30 seconds,
+// and zero disables it.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		if d < 0 {
+			return fmt.Errorf("onelogin: negative timeout %v", d)
+		}
+		c.timeout = d
+		return nil
+	}
+}
+
+// WithAccessToken makes the client use a token obtained out of band, such as
+// from a secrets manager, instead of issuing one from the client credentials.
+// A zero expiresAt means the token never expires. WithClock, if any, must come first.
+// Once expired, the token is refreshed if a refresh token was given with
+// WithRefreshToken, otherwise the requests fail with ErrAccessTokenExpired.
+func WithAccessToken(token string, expiresAt time.Time) ClientOption {
+	return func(c *Client) error {
+		if token == "" {
+			return fmt.Errorf("onelogin: empty access token")
+		}
+
+		expiresIn := int64(math.MaxInt32)
+		if !expiresAt.IsZero() {
+			expiresIn = int64(expiresAt.Sub(c.now()) / time.Second)
+		}
+
+		c.oauthToken = &oauthToken{
+			AccessToken: token,
+			CreatedAt:   c.now(),
+			ExpiresIn:   expiresIn,
+			TokenType:   "bearer",
+			client:      c,
+			provided:    true,
+		}
+		return nil
+	}
+}
+
+// WithRefreshToken sets the refresh token of the token given with WithAccessToken,
+// which must come first.
+func WithRefreshToken(refreshToken string) ClientOption {
+	return func(c *Client) error {
+		if c.oauthToken == nil || !c.oauthToken.provided {
+			return fmt.Errorf("onelogin: WithRefreshToken requires WithAccessToken")
+		}
+		c.oauthToken.refreshToken = refreshToken
+		return nil
+	}
+}
+
+// WithRevokeOnClose makes Close revoke the current token.
+func WithRevokeOnClose() ClientOption {
+	return func(c *Client) error {
+		c.revokeOnClose = true
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package onelogin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestWithDefaultHeadersReserved(t *testing.T) {
+	for _, key := range []string{"Authorization", "Content-Type", "Accept", "User-Agent", "Accept-Language", "authorization"} {
+		t.Run(key, func(t *testing.T) {
+			_, err := onelogin.NewClient("id", "secret", "example", onelogin.WithDefaultHeaders(map[string]string{key: "value"}))
+			if err == nil {
+				t.Errorf("got no error for the %s default header", key)
+			}
+		})
+	}
+}
+
+func TestWithDefaultHeaders(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+	s.HandleJSON("GET", "/api/2/users/1", http.StatusOK, map[string]interface{}{"id": 1})
+
+	c := s.Client(onelogin.WithDefaultHeaders(map[string]string{"X-Correlation-Id": "correlation"}))
+	if _, err := c.Users.Get(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range s.Requests() {
+		if r.Path == "/api/2/users/1" && r.Header.Get("X-Correlation-Id") != "correlation" {
+			t.Errorf("got X-Correlation-Id %q, want %q", r.Header.Get("X-Correlation-Id"), "correlation")
+		}
+	}
+}
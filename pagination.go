@@ -0,0 +1,111 @@
+package onelogin
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of results per page requested to the v2 list
+// endpoints when ListOptions.Limit is not set.
+const DefaultPageSize = 100
+
+// ListOptions configures the pagination of the v2 list endpoints.
+type ListOptions struct {
+	// Limit is the number of results per page, DefaultPageSize when zero.
+	Limit int `url:"limit,omitempty"`
+
+	// Cursor is the After-Cursor of the previous page.
+	Cursor string `url:"cursor,omitempty"`
+
+	// Fields restricts the returned fields to the given ones, to reduce the size
+	// of the pages. The other fields are left to their zero value.
+	Fields []string `url:"fields,comma,omitempty"`
+}
+
+// GetOptions configures the v2 endpoints returning a single resource.
+type GetOptions struct {
+	// Fields restricts the returned fields to the given ones.
+	// The other fields are left to their zero value.
+	Fields []string `url:"fields,comma,omitempty"`
+}
+
+// A pager walks through the pages of a v2 list endpoint, following the
+// After-Cursor response header.
+type pager struct {
+	client *Client
+	path   string
+	opts   interface{}
+
+	// cursorParam and pageSize differ for the v1 endpoints, which take an
+	// after_cursor and return smaller pages.
+	cursorParam string
+	pageSize    int
+
+	cursor string
+	done   bool
+}
+
+func newPager(c *Client, path string, opts interface{}) *pager {
+	return &pager{
+		client:      c,
+		path:        path,
+		opts:        opts,
+		cursorParam: "cursor",
+		pageSize:    DefaultPageSize,
+	}
+}
+
+// next fetches the next page into v.
+// It returns false, without fetching anything, once the last page has been fetched.
+func (p *pager) next(ctx context.Context, v interface{}) (bool, error) {
+	if p.done {
+		return false, nil
+	}
+
+	uu, err := addOptions(p.path, p.opts)
+	if err != nil {
+		return false, err
+	}
+
+	u, err := url.Parse(uu)
+	if err != nil {
+		return false, err
+	}
+
+	q := u.Query()
+	if q.Get("limit") == "" {
+		q.Set("limit", strconv.Itoa(p.pageSize))
+	}
+	if c := q.Get("cursor"); c != "" && p.cursorParam != "cursor" {
+		// The Cursor of the ListOptions.
+		q.Del("cursor")
+		q.Set(p.cursorParam, c)
+	}
+	if p.cursor != "" {
+		q.Set(p.cursorParam, p.cursor)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := p.client.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if err := p.client.AddAuthorization(ctx, req); err != nil {
+		return false, err
+	}
+
+	resp, err := p.client.Do(ctx, req, v)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.PaginationAfterCursor == nil || *resp.PaginationAfterCursor == "" {
+		p.done = true
+	} else {
+		p.cursor = *resp.PaginationAfterCursor
+	}
+
+	return true, nil
+}
@@ -0,0 +1,60 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// parallel calls fn for each i in [0, n), running at most limit calls at a time,
+// or all of them when limit isn't positive.
+// The context passed to fn is canceled as soon as a call fails, so the other
+// calls stop early. The error of the first failed call is returned, rather than
+// the cancellations it caused in the other ones.
+func parallel(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = n
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+
+			errs[i] = fn(ctx, i)
+			if errs[i] != nil {
+				// No need to run the other calls.
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// PrivilegesService handles communications with the privileges API of OneLogin,
+// used to delegate the administration of the account.
+type PrivilegesService service
+
+// Privilege grants the permissions of its policy to the roles and users it's assigned to.
+type Privilege struct {
+	ID          string           `json:"id,omitempty"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Privilege   *PrivilegePolicy `json:"privilege"`
+}
+
+// PrivilegePolicy is the policy document of a privilege.
+type PrivilegePolicy struct {
+	Version   string                `json:"Version"`
+	Statement []*PrivilegeStatement `json:"Statement"`
+}
+
+// PrivilegeStatement allows the Actions on the resources within Scope.
+type PrivilegeStatement struct {
+	Effect string   `json:"Effect"`
+	Action []string `json:"Action"`
+	Scope  []string `json:"Scope"`
+}
+
+type privilegeRolesParams struct {
+	Roles []int64 `json:"roles"`
+}
+
+type privilegeUsersParams struct {
+	Users []int64 `json:"users"`
+}
+
+// List returns all the privileges.
+func (s *PrivilegesService) List(ctx context.Context) ([]*Privilege, error) {
+	u := "/api/1/privileges"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var privileges []*Privilege
+	if _, err := s.client.Do(ctx, req, &privileges); err != nil {
+		return nil, err
+	}
+
+	return privileges, nil
+}
+
+// Get returns a privilege.
+func (s *PrivilegesService) Get(ctx context.Context, id string) (*Privilege, error) {
+	u := fmt.Sprintf("/api/1/privileges/%s", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var privilege Privilege
+	if _, err := s.client.Do(ctx, req, &privilege); err != nil {
+		return nil, err
+	}
+
+	return &privilege, nil
+}
+
+// Create creates a privilege, and returns it with its id.
+func (s *PrivilegesService) Create(ctx context.Context, privilege *Privilege) (*Privilege, error) {
+	return s.save(ctx, "POST", "/api/1/privileges", privilege)
+}
+
+// Update replaces the name, description and policy of a privilege. They are all
+// sent, so privilege should be the one returned by Get, modified.
+func (s *PrivilegesService) Update(ctx context.Context, id string, privilege *Privilege) (*Privilege, error) {
+	return s.save(ctx, "PUT", fmt.Sprintf("/api/1/privileges/%s", id), privilege)
+}
+
+func (s *PrivilegesService) save(ctx context.Context, method, u string, privilege *Privilege) (*Privilege, error) {
+	req, err := s.client.NewRequest(method, u, privilege)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var saved Privilege
+	if _, err := s.client.Do(ctx, req, &saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Delete deletes a privilege.
+func (s *PrivilegesService) Delete(ctx context.Context, id string) error {
+	return s.delete(ctx, fmt.Sprintf("/api/1/privileges/%s", id))
+}
+
+// GetRoles returns the ids of the roles the privilege is assigned to.
+func (s *PrivilegesService) GetRoles(ctx context.Context, id string) ([]int64, error) {
+	var r privilegeRolesParams
+	if err := s.get(ctx, fmt.Sprintf("/api/1/privileges/%s/roles", id), &r); err != nil {
+		return nil, err
+	}
+
+	return r.Roles, nil
+}
+
+// AddRoles assigns the privilege to the roles.
+func (s *PrivilegesService) AddRoles(ctx context.Context, id string, roleIDs []int64) error {
+	return s.post(ctx, fmt.Sprintf("/api/1/privileges/%s/roles", id), privilegeRolesParams{Roles: roleIDs})
+}
+
+// RemoveRoles removes the privilege from the roles, one role at a time.
+func (s *PrivilegesService) RemoveRoles(ctx context.Context, id string, roleIDs []int64) error {
+	for _, roleID := range roleIDs {
+		if err := s.delete(ctx, fmt.Sprintf("/api/1/privileges/%s/roles/%v", id, roleID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUsers returns the ids of the users the privilege is directly assigned to.
+func (s *PrivilegesService) GetUsers(ctx context.Context, id string) ([]int64, error) {
+	var r privilegeUsersParams
+	if err := s.get(ctx, fmt.Sprintf("/api/1/privileges/%s/users", id), &r); err != nil {
+		return nil, err
+	}
+
+	return r.Users, nil
+}
+
+// AddUsers assigns the privilege to the users.
+func (s *PrivilegesService) AddUsers(ctx context.Context, id string, userIDs []int64) error {
+	return s.post(ctx, fmt.Sprintf("/api/1/privileges/%s/users", id), privilegeUsersParams{Users: userIDs})
+}
+
+// RemoveUsers removes the privilege from the users, one user at a time.
+func (s *PrivilegesService) RemoveUsers(ctx context.Context, id string, userIDs []int64) error {
+	for _, userID := range userIDs {
+		if err := s.delete(ctx, fmt.Sprintf("/api/1/privileges/%s/users/%v", id, userID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PrivilegesService) get(ctx context.Context, u string, v interface{}) error {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, v)
+	return err
+}
+
+func (s *PrivilegesService) post(ctx context.Context, u string, body interface{}) error {
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+func (s *PrivilegesService) delete(ctx context.Context, u string) error {
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,80 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// RateLimit describes the rate limit of the current token.
+type RateLimit struct {
+	Limit        int `json:"X-RateLimit-Limit"`
+	Remaining    int `json:"X-RateLimit-Remaining"`
+	ResetSeconds int `json:"X-RateLimit-Reset"`
+}
+
+// GetRateLimit returns the rate limit of the current token.
+func (s *OauthService) GetRateLimit(ctx context.Context) (*RateLimit, error) {
+	u := "/auth/rate_limit"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var r RateLimit
+	if _, err := s.client.Do(ctx, req, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// RateLimit returns the rate limit reported by the X-RateLimit-* headers of
+// the latest response, or nil if no response carried them yet.
+func (c *Client) RateLimit() *RateLimit {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+
+	if c.rateLimit == nil {
+		return nil
+	}
+	r := *c.rateLimit
+	return &r
+}
+
+// parseRateLimit reads the X-RateLimit-* headers, returning nil when they are absent.
+func parseRateLimit(h http.Header) *RateLimit {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return nil
+	}
+
+	remaining, _ := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, _ := strconv.Atoi(h.Get("X-RateLimit-Reset"))
+
+	return &RateLimit{
+		Limit:        limit,
+		Remaining:    remaining,
+		ResetSeconds: reset,
+	}
+}
+
+// Ping checks the credentials of the client, by issuing a token if needed and
+// fetching its rate limit. It returns AuthenticationFailed when OneLogin rejects the
+// credentials, which makes it suitable for a startup or readiness check.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Oauth.GetRateLimit(ctx)
+
+	var e *APIError
+	if errors.As(err, &e) && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusBadRequest) {
+		return AuthenticationFailed
+	}
+
+	return err
+}
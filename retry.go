@@ -0,0 +1,105 @@
+package onelogin
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	maxRetryDelay         = 30 * time.Second
+)
+
+// send sends req, retrying it while it fails with a retryable status code.
+// The retries stop as soon as ctx is done. The redirections aren't followed when
+// ctx carries a noRedirectKey.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	hc := c.client
+	if ctx.Value(noRedirectKey{}) != nil {
+		// Copy the HTTP client, which may be shared, e.g. http.DefaultClient.
+		nc := *hc
+		nc.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		hc = &nc
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := hc.Do(req)
+		if err != nil || attempt >= c.MaxRetries || !isRetryable(req.Method, resp.StatusCode) {
+			return resp, err
+		}
+
+		// The body of a request can only be sent again if it can be rewound.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay := c.retryDelay(resp, attempt)
+		_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+		_ = resp.Body.Close()
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// isRetryable reports whether a request that failed with the status code should be retried.
+// OneLogin doesn't support idempotency keys: a POST failing with a 5xx status code
+// may still have created its resource, so it is only retried when rate limited.
+func isRetryable(method string, code int) bool {
+	if method == http.MethodPost {
+		return code == http.StatusTooManyRequests
+	}
+
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryDelay returns how long to wait before the next attempt.
+// The Retry-After header is honored when present, otherwise the delay grows
+// exponentially from RetryBaseDelay, with jitter.
+func (c *Client) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	if c.RetryBaseDelay <= 0 {
+		return 0
+	}
+
+	d := c.RetryBaseDelay << uint(attempt)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+
+	// Wait between half and the full backoff, so concurrent clients don't retry all at once.
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
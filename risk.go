@@ -0,0 +1,101 @@
+package onelogin
+
+import "context"
+
+// RiskService handles communications with the v2 risk API of OneLogin (Vigilance AI),
+// scoring the risk of the authentication attempts handled outside of OneLogin.
+type RiskService service
+
+// RiskUser is the user of a risk event or context.
+type RiskUser struct {
+	ID            string `json:"id"`
+	Name          string `json:"name,omitempty"`
+	Authenticated bool   `json:"authenticated"`
+}
+
+// RiskSource is the application originating a risk event or context.
+type RiskSource struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// RiskSession is the session of the user in the application.
+type RiskSession struct {
+	ID string `json:"id"`
+}
+
+// RiskDevice is the device of the user.
+type RiskDevice struct {
+	ID string `json:"id"`
+}
+
+// RiskContext describes an authentication attempt to score.
+type RiskContext struct {
+	IP        string       `json:"ip"`
+	UserAgent string       `json:"user_agent"`
+	User      *RiskUser    `json:"user"`
+	Source    *RiskSource  `json:"source,omitempty"`
+	Session   *RiskSession `json:"session,omitempty"`
+	Device    *RiskDevice  `json:"device,omitempty"`
+	// Fingerprint is the fingerprint of the browser of the user.
+	Fingerprint string `json:"fp,omitempty"`
+	// Published is the time of the attempt, in RFC 3339 format, now when empty.
+	Published string `json:"published,omitempty"`
+}
+
+// RiskEvent is an event of a user sent to train the risk engine, e.g. a login
+// or a password reset.
+type RiskEvent struct {
+	// Verb is the action of the user, e.g. "log-in" or "password-reset".
+	Verb string `json:"verb"`
+
+	RiskContext
+}
+
+// RiskScore is the risk of an authentication attempt, from 0 (no risk) to 100.
+// Triggers lists the reasons of the score.
+type RiskScore struct {
+	Score    int      `json:"score"`
+	Triggers []string `json:"triggers"`
+	Messages []string `json:"messages"`
+}
+
+// TrackEvent sends an event of a user to the risk engine, so it learns their
+// usual behavior.
+func (s *RiskService) TrackEvent(ctx context.Context, event *RiskEvent) error {
+	u := "/api/2/risk/events"
+
+	req, err := s.client.NewRequest("POST", u, event)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// GetScore returns the risk score of an authentication attempt, to decide
+// whether to require additional verification.
+func (s *RiskService) GetScore(ctx context.Context, rc *RiskContext) (*RiskScore, error) {
+	u := "/api/2/risk/verify"
+
+	req, err := s.client.NewRequest("POST", u, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var score RiskScore
+	if _, err := s.client.Do(ctx, req, &score); err != nil {
+		return nil, err
+	}
+
+	return &score, nil
+}
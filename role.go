@@ -5,9 +5,14 @@ import "golang.org/x/net/context"
 // RoleService deals with OneLogin roles.
 type RoleService service
 
+// Role represents a OneLogin role.
+// Apps, Users and Admins are only returned by the v2 API.
 type Role struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Apps   []int64 `json:"apps"`
+	Users  []int64 `json:"users"`
+	Admins []int64 `json:"admins"`
 }
 
 // GetRoles returns all the OneLogin Roles.
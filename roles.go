@@ -0,0 +1,211 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+)
+
+// RolesService handles communications with the v2 roles API of OneLogin.
+type RolesService service
+
+// RoleListOptions filters the roles returned by RolesService.List.
+type RoleListOptions struct {
+	ListOptions
+
+	Name string `url:"name,omitempty"`
+}
+
+type roleParams struct {
+	Name string  `json:"name"`
+	Apps []int64 `json:"apps,omitempty"`
+}
+
+// List returns all the OneLogin roles matching opts.
+func (s *RolesService) List(ctx context.Context, opts *RoleListOptions) ([]*Role, error) {
+	p := newPager(s.client, "/api/2/roles", opts)
+
+	var roles []*Role
+	for {
+		var rs []*Role
+		ok, err := p.next(ctx, &rs)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return roles, nil
+		}
+		roles = append(roles, rs...)
+	}
+}
+
+// Get returns a OneLogin role.
+func (s *RolesService) Get(ctx context.Context, id int64) (*Role, error) {
+	return s.GetWithOptions(ctx, id, nil)
+}
+
+// GetWithOptions returns a OneLogin role, configured by opts.
+func (s *RolesService) GetWithOptions(ctx context.Context, id int64, opts *GetOptions) (*Role, error) {
+	u, err := addOptions(fmt.Sprintf("/api/2/roles/%v", id), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var role Role
+	if _, err := s.client.Do(ctx, req, &role); err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// Create creates a role giving access to the given apps, and returns its id.
+func (s *RolesService) Create(ctx context.Context, name string, appIDs ...int64) (int64, error) {
+	u := "/api/2/roles"
+
+	req, err := s.client.NewRequest("POST", u, roleParams{Name: name, Apps: appIDs})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return 0, err
+	}
+
+	var r idResponse
+	if _, err := s.client.Do(ctx, req, &r); err != nil {
+		return 0, err
+	}
+
+	return r.ID, nil
+}
+
+// Update renames a role.
+func (s *RolesService) Update(ctx context.Context, id int64, name string) error {
+	u := fmt.Sprintf("/api/2/roles/%v", id)
+
+	req, err := s.client.NewRequest("PUT", u, roleParams{Name: name})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Delete deletes a role.
+func (s *RolesService) Delete(ctx context.Context, id int64) error {
+	u := fmt.Sprintf("/api/2/roles/%v", id)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// RoleUser is a user assigned to a role, or administering it.
+type RoleUser struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// GetUsers returns all the users assigned to a role, walking through all the pages.
+func (s *RolesService) GetUsers(ctx context.Context, roleID int64) ([]*RoleUser, error) {
+	return s.listUsers(ctx, fmt.Sprintf("/api/2/roles/%v/users", roleID), nil)
+}
+
+// AddUsers assigns a role to the users.
+func (s *RolesService) AddUsers(ctx context.Context, roleID int64, userIDs []int64) error {
+	return s.updateUsers(ctx, "POST", fmt.Sprintf("/api/2/roles/%v/users", roleID), userIDs)
+}
+
+// RemoveUsers removes a role from the users.
+func (s *RolesService) RemoveUsers(ctx context.Context, roleID int64, userIDs []int64) error {
+	return s.updateUsers(ctx, "DELETE", fmt.Sprintf("/api/2/roles/%v/users", roleID), userIDs)
+}
+
+// AddUsersBulk assigns a role to many users, like AddUsers, and reports the
+// outcome for every user.
+func (s *RolesService) AddUsersBulk(ctx context.Context, roleID int64, userIDs []int64) (BulkResults, error) {
+	return bulk(ctx, userIDs, func(ctx context.Context, ids []int64) error {
+		return s.AddUsers(ctx, roleID, ids)
+	})
+}
+
+// RemoveUsersBulk removes a role from many users, like RemoveUsers, and reports
+// the outcome for every user.
+func (s *RolesService) RemoveUsersBulk(ctx context.Context, roleID int64, userIDs []int64) (BulkResults, error) {
+	return bulk(ctx, userIDs, func(ctx context.Context, ids []int64) error {
+		return s.RemoveUsers(ctx, roleID, ids)
+	})
+}
+
+// GetAdmins returns all the users administering a role.
+func (s *RolesService) GetAdmins(ctx context.Context, roleID int64) ([]*RoleUser, error) {
+	return s.listUsers(ctx, fmt.Sprintf("/api/2/roles/%v/admins", roleID), nil)
+}
+
+// AddAdmins makes the users administrators of a role, so they can manage its
+// users without being account administrators.
+func (s *RolesService) AddAdmins(ctx context.Context, roleID int64, userIDs []int64) error {
+	return s.updateUsers(ctx, "POST", fmt.Sprintf("/api/2/roles/%v/admins", roleID), userIDs)
+}
+
+// RemoveAdmins removes the users from the administrators of a role.
+func (s *RolesService) RemoveAdmins(ctx context.Context, roleID int64, userIDs []int64) error {
+	return s.updateUsers(ctx, "DELETE", fmt.Sprintf("/api/2/roles/%v/admins", roleID), userIDs)
+}
+
+// listUsers returns the users of all the pages of u.
+func (s *RolesService) listUsers(ctx context.Context, u string, opts interface{}) ([]*RoleUser, error) {
+	p := newPager(s.client, u, opts)
+
+	var users []*RoleUser
+	for {
+		var us []*RoleUser
+		ok, err := p.next(ctx, &us)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return users, nil
+		}
+		users = append(users, us...)
+	}
+}
+
+// updateUsers sends the user ids to u, which takes them as a bare array.
+func (s *RolesService) updateUsers(ctx context.Context, method, u string, userIDs []int64) error {
+	req, err := s.client.NewRequest(method, u, userIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,116 @@
+package onelogin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestGenerateSAMLAssertion(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	s.HandleJSON("POST", "/api/2/saml_assertion", http.StatusOK, map[string]interface{}{
+		"data":    "PHNhbWxwOlJlc3BvbnNlLz4=",
+		"message": "Success",
+	})
+
+	c := s.Client()
+	a, err := c.Oauth.GenerateSAMLAssertion(context.Background(), "ada", "password", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.SAMLResponse != "PHNhbWxwOlJlc3BvbnNlLz4=" || a.StateToken != "" {
+		t.Errorf("got assertion %+v, want the SAML response", a)
+	}
+}
+
+func TestGenerateSAMLAssertionMFA(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	s.HandleJSON("POST", "/api/2/saml_assertion", http.StatusOK, map[string]interface{}{
+		"state_token": "state-token",
+		"message":     "MFA is required for this user",
+		"devices": []map[string]interface{}{
+			{"device_id": 2, "device_type": "OneLogin Protect"},
+		},
+		"callback_url": "https://api.us.onelogin.com/api/2/saml_assertion/verify_factor",
+		"user": map[string]interface{}{
+			"id": 1, "username": "ada", "email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace",
+		},
+	})
+
+	c := s.Client()
+	a, err := c.Oauth.GenerateSAMLAssertion(context.Background(), "ada", "password", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.SAMLResponse != "" || a.StateToken != "state-token" {
+		t.Errorf("got assertion %+v, want the MFA details", a)
+	}
+	if len(a.Devices) != 1 || a.Devices[0].ID != 2 || a.Devices[0].Type != "OneLogin Protect" {
+		t.Errorf("got devices %v, want the OneLogin Protect one", a.Devices)
+	}
+	if a.User == nil || a.User.ID != 1 {
+		t.Errorf("got user %+v, want the user 1", a.User)
+	}
+}
+
+func TestVerifySAMLFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    map[string]interface{}
+		otp     string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "otp",
+			status: http.StatusOK,
+			body:   map[string]interface{}{"data": "PHNhbWxwOlJlc3BvbnNlLz4=", "message": "Success"},
+			otp:    "123456",
+			want:   "PHNhbWxwOlJlc3BvbnNlLz4=",
+		},
+		{
+			name:    "push pending",
+			status:  http.StatusOK,
+			body:    map[string]interface{}{"message": "Authentication pending on OL Protect"},
+			wantErr: onelogin.ErrMFAPending,
+		},
+		{
+			name:   "rejected",
+			status: http.StatusUnauthorized,
+			body: map[string]interface{}{
+				"statusCode": http.StatusUnauthorized,
+				"name":       "Unauthorized",
+				"message":    "Failed authentication with this factor",
+			},
+			otp:     "000000",
+			wantErr: onelogin.MFA,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			s.HandleJSON("POST", "/api/2/saml_assertion/verify_factor", tt.status, tt.body)
+
+			c := s.Client()
+			got, err := c.Oauth.VerifySAMLFactor(context.Background(), 1, 2, "state-token", tt.otp)
+			if err != tt.wantErr {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got SAML response %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const sessionURL = "https://%s.onelogin.com/session_via_api_token"
+
+// ErrMissingSessionToken is returned when creating a session without session token.
+var ErrMissingSessionToken = errors.New("missing session token")
+
+// CreateSession exchanges the session token of an authenticated user, returned by
+// either Authenticate or VerifyFactor, for the cookies of a OneLogin browser session.
+// A session token can only be exchanged once, before it expires.
+func (s *OauthService) CreateSession(ctx context.Context, sessionToken string) ([]*http.Cookie, error) {
+	return s.CreateSessionWithSubdomain(ctx, sessionToken, "")
+}
+
+// CreateSessionWithSubdomain creates a session like CreateSession, on the given
+// subdomain rather than the one of the client when not empty.
+func (s *OauthService) CreateSessionWithSubdomain(ctx context.Context, sessionToken, subdomain string) ([]*http.Cookie, error) {
+	if sessionToken == "" {
+		return nil, ErrMissingSessionToken
+	}
+
+	subdomain, err := s.client.subdomainOr(subdomain)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{"session_token": {sessionToken}}
+	req, err := http.NewRequest("POST", s.client.sessionEndpoint(subdomain), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.client.UserAgent != "" {
+		req.Header.Set("User-Agent", s.client.UserAgent)
+	}
+
+	// OneLogin sets the cookies of the session on a redirection, which must not
+	// be followed not to lose them.
+	ctx = context.WithValue(ctx, noRedirectKey{}, true)
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		var e *APIError
+		if !errors.As(err, &e) || e.StatusCode < 300 || e.StatusCode > 399 {
+			return nil, err
+		}
+	}
+
+	return resp.Cookies(), nil
+}
+
+// sessionEndpoint returns the URL sessions are created at, on the subdomain,
+// unless the client targets a mock server or a gateway set with WithBaseURL.
+func (c *Client) sessionEndpoint(subdomain string) string {
+	if c.BaseURL != nil && !strings.HasSuffix(c.BaseURL.Hostname(), ".onelogin.com") {
+		return c.BaseURL.ResolveReference(&url.URL{Path: "session_via_api_token"}).String()
+	}
+
+	return buildURL(sessionURL, subdomain)
+}
+
+// noRedirectKey is the context key making the client return the redirections
+// instead of following them.
+type noRedirectKey struct{}
+
+// ErrMissingSubdomain is returned by the methods requiring the subdomain of the
+// account when neither the client nor the call has one.
+var ErrMissingSubdomain = errors.New("missing subdomain")
+
+// subdomainOr returns subdomain, or the subdomain of the client when empty.
+// ErrMissingSubdomain is returned when both are empty.
+func (c *Client) subdomainOr(subdomain string) (string, error) {
+	if subdomain == "" {
+		subdomain = c.subdomain
+	}
+	if subdomain == "" {
+		return "", ErrMissingSubdomain
+	}
+
+	return subdomain, nil
+}
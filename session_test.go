@@ -0,0 +1,45 @@
+package onelogin_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestCreateSession(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+
+	s.HandleFunc("POST", "/session_via_api_token", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sub_session_onelogin.com", Value: "session"})
+		http.Redirect(w, r, "/portal", http.StatusFound)
+	})
+	s.HandleFunc("GET", "/portal", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "portal", Value: "portal"})
+	})
+
+	c := s.Client()
+	cookies, err := c.Oauth.CreateSession(context.Background(), "session-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cookies) != 1 || cookies[0].Name != "sub_session_onelogin.com" || cookies[0].Value != "session" {
+		t.Errorf("got cookies %v, want the session cookie", cookies)
+	}
+
+	reqs := s.Requests()
+	if len(reqs) != 1 || reqs[0].Path != "/session_via_api_token" {
+		t.Fatalf("got requests %v, want a single session request", reqs)
+	}
+	form, err := url.ParseQuery(string(reqs[0].Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := form.Get("session_token"); got != "session-token" {
+		t.Errorf("got session_token %q, want %q", got, "session-token")
+	}
+}
@@ -0,0 +1,222 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// SmartHooksService handles communications with the v2 Smart Hooks API of OneLogin.
+type SmartHooksService service
+
+// Hook is a Smart Hook, running custom javascript during the login or provisioning flows.
+type Hook struct {
+	ID             string            `json:"id,omitempty"`
+	Type           string            `json:"type"`
+	Disabled       bool              `json:"disabled"`
+	Runtime        string            `json:"runtime"`
+	ContextVersion string            `json:"context_version,omitempty"`
+	Retries        int               `json:"retries"`
+	Timeout        int               `json:"timeout"`
+	EnvVars        []string          `json:"env_vars"`
+	Packages       map[string]string `json:"packages"`
+	// Function is the base64 encoded source of the hook.
+	Function string `json:"function"`
+
+	Status    string `json:"status,omitempty"`
+	CreatedAt *Time  `json:"created_at,omitempty"`
+	UpdatedAt *Time  `json:"updated_at,omitempty"`
+}
+
+// Source returns the decoded javascript source of the hook.
+func (h *Hook) Source() (string, error) {
+	b, err := base64.StdEncoding.DecodeString(h.Function)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// List returns all the hooks.
+func (s *SmartHooksService) List(ctx context.Context) ([]*Hook, error) {
+	u := "/api/2/hooks"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var hooks []*Hook
+	if _, err := s.client.Do(ctx, req, &hooks); err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// Get returns a hook.
+func (s *SmartHooksService) Get(ctx context.Context, id string) (*Hook, error) {
+	u := fmt.Sprintf("/api/2/hooks/%s", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var hook Hook
+	if _, err := s.client.Do(ctx, req, &hook); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// Create creates a hook running the javascript source, which gets base64 encoded
+// into the Function of the hook.
+func (s *SmartHooksService) Create(ctx context.Context, hook *Hook, source string) (*Hook, error) {
+	return s.save(ctx, "POST", "/api/2/hooks", hook, source)
+}
+
+// Update replaces a hook. All its fields are sent, so hook should be the one
+// returned by Get, modified. The Function of the hook is replaced by the base64
+// encoded source, unless source is empty.
+func (s *SmartHooksService) Update(ctx context.Context, id string, hook *Hook, source string) (*Hook, error) {
+	return s.save(ctx, "PUT", fmt.Sprintf("/api/2/hooks/%s", id), hook, source)
+}
+
+func (s *SmartHooksService) save(ctx context.Context, method, u string, hook *Hook, source string) (*Hook, error) {
+	h := *hook
+	// Don't send back the read-only fields of a hook returned by Get.
+	h.ID, h.Status = "", ""
+	h.CreatedAt, h.UpdatedAt = nil, nil
+	if source != "" {
+		h.Function = base64.StdEncoding.EncodeToString([]byte(source))
+	}
+
+	req, err := s.client.NewRequest(method, u, &h)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var saved Hook
+	if _, err := s.client.Do(ctx, req, &saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Delete deletes a hook.
+func (s *SmartHooksService) Delete(ctx context.Context, id string) error {
+	u := fmt.Sprintf("/api/2/hooks/%s", id)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// EnvVar is an environment variable available to the hooks.
+// Its value is write-only: OneLogin never returns it.
+type EnvVar struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt Time   `json:"created_at"`
+	UpdatedAt Time   `json:"updated_at"`
+}
+
+type envVarParams struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+// ListEnvVars returns all the hook environment variables, without their values.
+func (s *SmartHooksService) ListEnvVars(ctx context.Context) ([]*EnvVar, error) {
+	u := "/api/2/hooks/envs"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var envVars []*EnvVar
+	if _, err := s.client.Do(ctx, req, &envVars); err != nil {
+		return nil, err
+	}
+
+	return envVars, nil
+}
+
+// GetEnvVar returns a hook environment variable, without its value.
+func (s *SmartHooksService) GetEnvVar(ctx context.Context, id string) (*EnvVar, error) {
+	return s.envVar(ctx, "GET", fmt.Sprintf("/api/2/hooks/envs/%s", id), nil)
+}
+
+// CreateEnvVar creates a hook environment variable.
+func (s *SmartHooksService) CreateEnvVar(ctx context.Context, name, value string) (*EnvVar, error) {
+	return s.envVar(ctx, "POST", "/api/2/hooks/envs", envVarParams{Name: name, Value: value})
+}
+
+// UpdateEnvVar replaces the value of a hook environment variable.
+func (s *SmartHooksService) UpdateEnvVar(ctx context.Context, id, value string) (*EnvVar, error) {
+	return s.envVar(ctx, "PUT", fmt.Sprintf("/api/2/hooks/envs/%s", id), envVarParams{Value: value})
+}
+
+func (s *SmartHooksService) envVar(ctx context.Context, method, u string, body interface{}) (*EnvVar, error) {
+	req, err := s.client.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var envVar EnvVar
+	if _, err := s.client.Do(ctx, req, &envVar); err != nil {
+		return nil, err
+	}
+
+	return &envVar, nil
+}
+
+// DeleteEnvVar deletes a hook environment variable.
+func (s *SmartHooksService) DeleteEnvVar(ctx context.Context, id string) error {
+	u := fmt.Sprintf("/api/2/hooks/envs/%s", id)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
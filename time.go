@@ -0,0 +1,57 @@
+package onelogin
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// timeLayouts are the formats of the timestamps returned by OneLogin: RFC 3339,
+// except for the expiration of the session tokens.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05 -0700",
+}
+
+// Time is a timestamp returned by OneLogin.
+// A null or empty timestamp decodes to the zero time, and an invalid one fails
+// the decoding of the response instead of being silently ignored.
+type Time struct {
+	time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+
+	s := string(data[1 : len(data)-1])
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	for _, layout := range timeLayouts {
+		if tt, err := time.Parse(layout, s); err == nil {
+			t.Time = tt
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid timestamp %q", s)
+}
+
+// MarshalJSON implements json.Marshaler, encoding the zero time as null.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + t.Format(time.RFC3339Nano) + `"`), nil
+}
@@ -0,0 +1,94 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Token is an oauth token, as persisted by a TokenStore.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	AccountID    int       `json:"account_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresIn    int64     `json:"expires_in"`
+	TokenType    string    `json:"token_type"`
+}
+
+// A TokenStore persists the oauth token of a client.
+type TokenStore interface {
+	// Load returns the persisted token, or nil if there is none.
+	Load() (*Token, error)
+	// Save persists the token. A nil token clears the persisted one.
+	Save(*Token) error
+}
+
+// FileTokenStore is a TokenStore persisting the token as JSON into a file,
+// only readable by its owner.
+type FileTokenStore struct {
+	Path string
+}
+
+// NewFileTokenStore returns a FileTokenStore persisting the token into path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// Load reads the token from the file, returning nil if it doesn't exist.
+func (s *FileTokenStore) Load() (*Token, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t Token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Save writes the token into the file with 0600 permissions, or removes the file
+// when t is nil.
+func (s *FileTokenStore) Save(t *Token) error {
+	if t == nil {
+		if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, created with 0600 permissions, so the
+	// token is replaced atomically.
+	f, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
@@ -2,6 +2,7 @@ package onelogin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -10,25 +11,25 @@ type UserService service
 
 // User represents a OneLogin user.
 type User struct {
-	ActivatedAt          string            `json:"activated_at"`
-	CreatedAt            string            `json:"created_at"`
+	ActivatedAt          Time              `json:"activated_at"`
+	CreatedAt            Time              `json:"created_at"`
 	Email                string            `json:"email"`
 	Username             string            `json:"username"`
 	FirstName            string            `json:"firstname"`
 	GroupID              int64             `json:"group_id"`
 	ID                   int64             `json:"id"`
 	InvalidLoginAttempts int64             `json:"invalid_login_attempts"`
-	InvitationSentAt     string            `json:"invitation_sent_at"`
-	LastLogin            string            `json:"last_login"`
+	InvitationSentAt     Time              `json:"invitation_sent_at"`
+	LastLogin            Time              `json:"last_login"`
 	LastName             string            `json:"lastname"`
-	LockedUntil          string            `json:"locked_until"`
+	LockedUntil          Time              `json:"locked_until"`
 	Notes                string            `json:"notes"`
 	OpenidName           string            `json:"openid_name"`
 	LocaleCode           string            `json:"locale_code"`
-	PasswordChangedAt    string            `json:"password_changed_at"`
+	PasswordChangedAt    Time              `json:"password_changed_at"`
 	Phone                string            `json:"phone"`
 	Status               int64             `json:"status"`
-	UpdatedAt            string            `json:"updated_at"`
+	UpdatedAt            Time              `json:"updated_at"`
 	DistinguishedName    string            `json:"distinguished_name"`
 	ExternalID           string            `json:"external_id"`
 	DirectoryID          int64             `json:"directory_id"`
@@ -40,6 +41,26 @@ type User struct {
 	CustomAttributes     map[string]string `json:"custom_attributes"`
 }
 
+// UnmarshalJSON decodes a user returned by either the v1 or the v2 API,
+// which respectively name the role ids role_id and role_ids.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	var v struct {
+		user
+		RoleIDsV2 []int64 `json:"role_ids"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*u = User(v.user)
+	if v.RoleIDsV2 != nil {
+		u.RoleIDs = v.RoleIDsV2
+	}
+
+	return nil
+}
+
 type getUserQuery struct {
 	AfterCursor string `url:"after_cursor,omitempty"`
 }
@@ -48,7 +69,7 @@ type getUserQuery struct {
 type MFAVerification struct {
 	DeviceId   int    `json:"device_id,string"`
 	StateToken string `json:"state_token"`
-	OTPToken   string `json:"otp_token"`
+	OTPToken   string `json:"otp_token,omitempty"`
 }
 
 // GetUsers returns all the OneLogin users.
@@ -0,0 +1,1027 @@
+package onelogin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsersService handles communications with the v2 users API of OneLogin, or the
+// v1 one when configured with WithUsersAPIVersion.
+type UsersService service
+
+// UserListOptions filters the users returned by UsersService.List.
+type UserListOptions struct {
+	ListOptions
+
+	Email    string `url:"email,omitempty"`
+	Username string `url:"username,omitempty"`
+
+	// CreatedSince and CreatedUntil restrict the users to the ones created in that range.
+	CreatedSince time.Time `url:"created_since,omitempty"`
+	CreatedUntil time.Time `url:"created_until,omitempty"`
+
+	// UpdatedSince and UpdatedUntil restrict the users to the ones updated in that range.
+	UpdatedSince time.Time `url:"updated_since,omitempty"`
+	UpdatedUntil time.Time `url:"updated_until,omitempty"`
+
+	// CustomAttributes restricts the users to the ones whose custom attributes,
+	// by shortname, have the given values.
+	CustomAttributes CustomAttributeFilters `url:"custom_attributes,omitempty"`
+}
+
+// CustomAttributeFilters filters users on the values of their custom attributes,
+// by shortname. They are sent as custom_attributes.<shortname>=<value>.
+type CustomAttributeFilters map[string]string
+
+// EncodeValues implements query.Encoder.
+func (f CustomAttributeFilters) EncodeValues(key string, v *url.Values) error {
+	for name, value := range f {
+		v.Set(key+"."+name, value)
+	}
+
+	return nil
+}
+
+// List returns all the OneLogin users matching opts, walking through all the pages.
+// Use ListPage or Pager to fetch them one page at a time.
+func (s *UsersService) List(ctx context.Context, opts *UserListOptions) ([]*User, error) {
+	p := s.Pager(opts)
+
+	var users []*User
+	for {
+		us, ok, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return users, nil
+		}
+		users = append(users, us...)
+	}
+}
+
+// ListPage returns a single page of the OneLogin users matching opts.
+func (s *UsersService) ListPage(ctx context.Context, opts *UserListOptions) ([]*User, error) {
+	users, _, err := s.Pager(opts).Next(ctx)
+	return users, err
+}
+
+// ListAll returns all the OneLogin users matching opts, like List.
+//
+// Deprecated: use List, which walks through all the pages like the List
+// methods of the other services.
+func (s *UsersService) ListAll(ctx context.Context, opts *UserListOptions) ([]*User, error) {
+	return s.List(ctx, opts)
+}
+
+// usersEpoch precedes the creation of any OneLogin user, bounding the windows of
+// ListAllParallel when opts has no CreatedSince.
+var usersEpoch = time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ListAllParallel returns all the OneLogin users matching opts like List, but
+// faster for large directories: the cursor pagination being sequential, the
+// creation dates range of opts (up to now when opts has no CreatedUntil) is split
+// into workers windows, whose users are fetched concurrently.
+// The users aren't returned in any particular order. The users created at the
+// boundary of two windows are only returned once.
+func (s *UsersService) ListAllParallel(ctx context.Context, opts *UserListOptions, workers int) ([]*User, error) {
+	if workers <= 1 {
+		return s.List(ctx, opts)
+	}
+
+	var base UserListOptions
+	if opts != nil {
+		base = *opts
+	}
+	base.Cursor = ""
+
+	since, until := base.CreatedSince, base.CreatedUntil
+	if since.IsZero() {
+		since = usersEpoch
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	window := until.Sub(since) / time.Duration(workers)
+	if window <= 0 {
+		return s.List(ctx, opts)
+	}
+
+	pages := make([][]*User, workers)
+	err := parallel(ctx, workers, 0, func(ctx context.Context, i int) error {
+		o := base
+		o.CreatedSince = since.Add(time.Duration(i) * window)
+		o.CreatedUntil = o.CreatedSince.Add(window)
+		if i == workers-1 {
+			o.CreatedUntil = until
+		}
+
+		var err error
+		pages[i], err = s.List(ctx, &o)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*User
+	seen := make(map[int64]bool)
+	for _, page := range pages {
+		for _, u := range page {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				users = append(users, u)
+			}
+		}
+	}
+
+	return users, nil
+}
+
+// Pager returns a UserPager iterating over the pages of the users matching opts.
+func (s *UsersService) Pager(opts *UserListOptions) *UserPager {
+	p := newPager(s.client, s.path(""), opts)
+	if s.client.usersAPIVersion == 1 {
+		p.cursorParam = "after_cursor"
+		p.pageSize = 50
+	}
+
+	return &UserPager{pager: p}
+}
+
+// ListUpdatedSince returns a UserPager iterating over the pages of the users
+// updated since t, for incremental syncs.
+func (s *UsersService) ListUpdatedSince(t time.Time) *UserPager {
+	return s.Pager(&UserListOptions{UpdatedSince: t})
+}
+
+// A UserPager iterates over pages of users, one request at a time.
+type UserPager struct {
+	*pager
+}
+
+// Next returns the next page of users.
+// It returns false once all the pages have been returned.
+func (p *UserPager) Next(ctx context.Context) ([]*User, bool, error) {
+	var users []*User
+	ok, err := p.next(ctx, &users)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return users, true, nil
+}
+
+// Get returns a OneLogin user.
+func (s *UsersService) Get(ctx context.Context, id int64) (*User, error) {
+	return s.GetWithOptions(ctx, id, nil)
+}
+
+// GetWithOptions returns a OneLogin user, configured by opts.
+func (s *UsersService) GetWithOptions(ctx context.Context, id int64, opts *GetOptions) (*User, error) {
+	u, err := addOptions(s.path("/%v", id), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if _, err := s.client.Do(ctx, req, &raw); err != nil {
+		return nil, err
+	}
+
+	return decodeUser(raw)
+}
+
+// path returns the path of the users API of the configured version, followed by
+// the formatted suffix.
+func (s *UsersService) path(format string, a ...interface{}) string {
+	return fmt.Sprintf("/api/%d/users", s.client.usersAPIVersion) + fmt.Sprintf(format, a...)
+}
+
+// decodeUser decodes a user returned either as a bare object by the v2 API, or
+// into an array by the v1 one.
+func decodeUser(raw json.RawMessage) (*User, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var users []*User
+		if err := json.Unmarshal(raw, &users); err != nil {
+			return nil, err
+		}
+		if len(users) == 0 || users[0] == nil {
+			return nil, ErrNotFound
+		}
+		return users[0], nil
+	}
+
+	var user User
+	if err := json.Unmarshal(raw, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+type userRolesParams struct {
+	RoleIDs []int64 `json:"role_id_array"`
+}
+
+// AddRoles assigns the roles to a user.
+func (s *UsersService) AddRoles(ctx context.Context, userID int64, roleIDs []int64) error {
+	return s.updateRoles(ctx, "PUT", userID, roleIDs)
+}
+
+// RemoveRoles removes the roles from a user.
+func (s *UsersService) RemoveRoles(ctx context.Context, userID int64, roleIDs []int64) error {
+	return s.updateRoles(ctx, "DELETE", userID, roleIDs)
+}
+
+// AddRolesBulk assigns many roles to a user, like AddRoles, and reports the
+// outcome for every role.
+func (s *UsersService) AddRolesBulk(ctx context.Context, userID int64, roleIDs []int64) (BulkResults, error) {
+	return bulk(ctx, roleIDs, func(ctx context.Context, ids []int64) error {
+		return s.AddRoles(ctx, userID, ids)
+	})
+}
+
+// RemoveRolesBulk removes many roles from a user, like RemoveRoles, and reports
+// the outcome for every role.
+func (s *UsersService) RemoveRolesBulk(ctx context.Context, userID int64, roleIDs []int64) (BulkResults, error) {
+	return bulk(ctx, roleIDs, func(ctx context.Context, ids []int64) error {
+		return s.RemoveRoles(ctx, userID, ids)
+	})
+}
+
+// ReconcileRoles assigns and removes roles so the user ends up with exactly the
+// desired ones, fetching the current roles and only sending the needed changes.
+// It returns the ids of the roles added and removed. When removing the roles
+// fails, the roles have already been added.
+func (s *UsersService) ReconcileRoles(ctx context.Context, userID int64, desired []int64) (added, removed []int64, err error) {
+	current, err := s.GetRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	has := make(map[int64]bool, len(current))
+	for _, id := range current {
+		has[id] = true
+	}
+	wants := make(map[int64]bool, len(desired))
+	for _, id := range desired {
+		if !has[id] && !wants[id] {
+			added = append(added, id)
+		}
+		wants[id] = true
+	}
+	for _, id := range current {
+		if !wants[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(added) > 0 {
+		if err := s.AddRoles(ctx, userID, added); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if err := s.RemoveRoles(ctx, userID, removed); err != nil {
+			return added, nil, err
+		}
+	}
+
+	return added, removed, nil
+}
+
+func (s *UsersService) updateRoles(ctx context.Context, method string, userID int64, roleIDs []int64) error {
+	u := fmt.Sprintf("/api/2/users/%v/roles", userID)
+
+	req, err := s.client.NewRequest(method, u, userRolesParams{RoleIDs: roleIDs})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// UserApp is an app a user can launch from their portal.
+type UserApp struct {
+	ID                  int64  `json:"id"`
+	Name                string `json:"name"`
+	IconURL             string `json:"icon_url"`
+	LoginID             int64  `json:"login_id"`
+	ProvisioningEnabled bool   `json:"provisioning_enabled"`
+	ProvisioningStatus  string `json:"provisioning_status"`
+	ProvisioningState   string `json:"provisioning_state"`
+
+	// AssignedVia holds the ids of the roles of the user giving access to the
+	// app. It is only set by GetAppAssignments, OneLogin doesn't return it.
+	AssignedVia []int64 `json:"-"`
+}
+
+// Direct reports whether the app is assigned to the user directly rather than
+// through one of their roles. It is only meaningful for the apps returned by
+// GetAppAssignments.
+func (a *UserApp) Direct() bool {
+	return len(a.AssignedVia) == 0
+}
+
+// GetApps returns the apps a user can launch, along their provisioning state.
+func (s *UsersService) GetApps(ctx context.Context, userID int64) ([]*UserApp, error) {
+	u := fmt.Sprintf("/api/2/users/%v/apps", userID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var apps []*UserApp
+	if _, err := s.client.Do(ctx, req, &apps); err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// GetAppAssignments returns the apps a user can launch like GetApps, setting the
+// roles of the user the apps are assigned via. OneLogin doesn't tell how an app
+// is assigned: the apps of the roles of the user are cross-referenced, and the
+// apps of none of them are reported as Direct.
+func (s *UsersService) GetAppAssignments(ctx context.Context, userID int64) ([]*UserApp, error) {
+	apps, err := s.GetApps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.GetRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	via := make(map[int64][]int64)
+	for _, role := range roles {
+		for _, id := range role.Apps {
+			via[id] = append(via[id], role.ID)
+		}
+	}
+	for _, app := range apps {
+		app.AssignedVia = via[app.ID]
+	}
+
+	return apps, nil
+}
+
+// GetAppProvisioning returns the app of a user, carrying the state of its
+// provisioning into the app. ErrNotFound is returned when the user can't launch
+// the app.
+// OneLogin's API has no endpoint retrying a failed provisioning: the pending
+// and failed tasks are retried or approved from the provisioning page of the
+// OneLogin admin portal.
+func (s *UsersService) GetAppProvisioning(ctx context.Context, userID, appID int64) (*UserApp, error) {
+	apps, err := s.GetApps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		if app.ID == appID {
+			return app, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// UserCreate holds the fields of a user to create.
+// Either Email or Username is required.
+type UserCreate struct {
+	Email            string            `json:"email,omitempty"`
+	Username         string            `json:"username,omitempty"`
+	FirstName        string            `json:"firstname,omitempty"`
+	LastName         string            `json:"lastname,omitempty"`
+	Title            string            `json:"title,omitempty"`
+	Department       string            `json:"department,omitempty"`
+	Company          string            `json:"company,omitempty"`
+	Phone            string            `json:"phone,omitempty"`
+	ExternalID       string            `json:"external_id,omitempty"`
+	GroupID          int64             `json:"group_id,omitempty"`
+	RoleIDs          []int64           `json:"role_ids,omitempty"`
+	CustomAttributes map[string]string `json:"custom_attributes,omitempty"`
+}
+
+// UserUpdate holds the fields of a user to update.
+// Only the non-nil fields are sent, leaving the others unchanged: set a field to
+// a pointer to its zero value, e.g. String(""), to clear it.
+// CustomAttributes only updates the attributes present in the map.
+type UserUpdate struct {
+	Email            *string           `json:"email,omitempty"`
+	Username         *string           `json:"username,omitempty"`
+	FirstName        *string           `json:"firstname,omitempty"`
+	LastName         *string           `json:"lastname,omitempty"`
+	Title            *string           `json:"title,omitempty"`
+	Department       *string           `json:"department,omitempty"`
+	Company          *string           `json:"company,omitempty"`
+	Phone            *string           `json:"phone,omitempty"`
+	ExternalID       *string           `json:"external_id,omitempty"`
+	GroupID          *int64            `json:"group_id,omitempty"`
+	State            *int64            `json:"state,omitempty"`
+	Status           *int64            `json:"status,omitempty"`
+	CustomAttributes map[string]string `json:"custom_attributes,omitempty"`
+}
+
+// Create creates a user.
+// Validation failures are returned as an *APIError listing the invalid fields.
+func (s *UsersService) Create(ctx context.Context, user *UserCreate) (*User, error) {
+	u := s.path("")
+
+	req, err := s.client.NewRequest("POST", u, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if _, err := s.client.Do(ctx, req, &raw); err != nil {
+		return nil, err
+	}
+
+	return decodeUser(raw)
+}
+
+// CreateOrGetUser returns the user with the email of user (or its username, when
+// it has no email), creating it when there is none. The returned bool reports
+// whether the user was created.
+// OneLogin doesn't support idempotency keys: this lets the creations be retried,
+// e.g. after a timeout, without provisioning duplicate users. The existing user
+// is returned as is, not updated with the fields of user.
+func (s *UsersService) CreateOrGetUser(ctx context.Context, user *UserCreate) (*User, bool, error) {
+	existing, err := s.findUser(ctx, user)
+	if err == nil {
+		return existing, false, nil
+	} else if err != ErrNotFound {
+		return nil, false, err
+	}
+
+	created, err := s.Create(ctx, user)
+	if err != nil {
+		var e *APIError
+		if errors.As(err, &e) && e.StatusCode == http.StatusUnprocessableEntity {
+			// The user may have been created concurrently.
+			if existing, ferr := s.findUser(ctx, user); ferr == nil {
+				return existing, false, nil
+			}
+		}
+		return nil, false, err
+	}
+
+	return created, true, nil
+}
+
+// findUser returns the user with the email, or else the username, of user.
+func (s *UsersService) findUser(ctx context.Context, user *UserCreate) (*User, error) {
+	if user.Email != "" {
+		return s.GetByEmail(ctx, user.Email)
+	} else if user.Username == "" {
+		return nil, ErrNotFound
+	}
+
+	users, err := s.List(ctx, &UserListOptions{Username: user.Username})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Username, user.Username) {
+			return u, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// Update updates the non-nil fields of a user.
+// Validation failures are returned as an *APIError listing the invalid fields.
+func (s *UsersService) Update(ctx context.Context, id int64, user *UserUpdate) (*User, error) {
+	u := s.path("/%v", id)
+
+	req, err := s.client.NewRequest("PUT", u, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if _, err := s.client.Do(ctx, req, &raw); err != nil {
+		return nil, err
+	}
+
+	return decodeUser(raw)
+}
+
+// Delete deletes a user.
+// An error matching ErrNotFound is returned when the user doesn't exist.
+func (s *UsersService) Delete(ctx context.Context, id int64) error {
+	u := s.path("/%v", id)
+
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// DeleteMany deletes the users one after the other, stopping at the first failure.
+// It returns the ids of the users deleted so far, even when an error occurs.
+func (s *UsersService) DeleteMany(ctx context.Context, ids []int64) (deleted []int64, err error) {
+	for _, id := range ids {
+		if err := s.Delete(ctx, id); err != nil {
+			return deleted, fmt.Errorf("deleting user %v: %w", id, err)
+		}
+		deleted = append(deleted, id)
+	}
+
+	return deleted, nil
+}
+
+type setPasswordParams struct {
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"password_confirmation"`
+	PasswordAlgorithm    string `json:"password_algorithm,omitempty"`
+	PasswordSalt         string `json:"password_salt,omitempty"`
+	ValidatePolicy       bool   `json:"validate_policy,omitempty"`
+}
+
+// SetPasswordClearText sets the password of a user, enforcing the password policy.
+// Policy violations are returned as an *APIError.
+// The password endpoints only exist on the v1 API, so they don't follow
+// WithUsersAPIVersion.
+func (s *UsersService) SetPasswordClearText(ctx context.Context, id int64, password string) error {
+	u := fmt.Sprintf("/api/1/users/set_password_clear_text/%v", id)
+
+	return s.setPassword(ctx, u, setPasswordParams{
+		Password:             password,
+		PasswordConfirmation: password,
+		ValidatePolicy:       true,
+	})
+}
+
+// SetPasswordUsingSalt sets the password of a user from its salted hash, computed
+// with the given algorithm (e.g. "salt+sha256"), typically during a migration.
+// Like SetPasswordClearText, it targets the v1 API whatever WithUsersAPIVersion.
+func (s *UsersService) SetPasswordUsingSalt(ctx context.Context, id int64, hash, salt string, algorithm string) error {
+	u := fmt.Sprintf("/api/1/users/set_password_using_salt/%v", id)
+
+	return s.setPassword(ctx, u, setPasswordParams{
+		Password:             hash,
+		PasswordConfirmation: hash,
+		PasswordAlgorithm:    algorithm,
+		PasswordSalt:         salt,
+	})
+}
+
+func (s *UsersService) setPassword(ctx context.Context, u string, p setPasswordParams) error {
+	req, err := s.client.NewRequest("PUT", u, p)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Logout terminates all the active sessions of a user.
+// An error matching ErrNotFound is returned when the user doesn't exist, other
+// failures are returned as an *APIError.
+func (s *UsersService) Logout(ctx context.Context, userID int64) error {
+	u := fmt.Sprintf("/api/1/users/%v/logout", userID)
+
+	req, err := s.client.NewRequest("PUT", u, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// EventTypeUserLoggedIn is the type of the events of the users logging into OneLogin.
+const EventTypeUserLoggedIn int64 = 5
+
+// sessionsLookback is how far back Sessions looks for logins.
+const sessionsLookback = 24 * time.Hour
+
+// Session is a login of a user into OneLogin, as recorded by its event.
+type Session struct {
+	EventID            int64
+	CreatedAt          Time
+	IPAddr             string
+	BrowserFingerprint string
+	RiskScore          int64
+}
+
+// Sessions returns the logins of a user into OneLogin during the last 24 hours.
+// OneLogin doesn't expose the sessions of the users: they are derived from the
+// login events, so they include the sessions that have since expired or been
+// terminated by a logout, and may miss the most recent logins, as the events are
+// recorded asynchronously.
+func (s *UsersService) Sessions(ctx context.Context, userID int64) ([]*Session, error) {
+	events, err := s.client.Events.List(ctx, &EventListOptions{
+		EventTypeID: EventTypeUserLoggedIn,
+		UserID:      userID,
+		Since:       time.Now().Add(-sessionsLookback),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(events))
+	for _, e := range events {
+		sessions = append(sessions, &Session{
+			EventID:            e.ID,
+			CreatedAt:          e.CreatedAt,
+			IPAddr:             e.IPAddr,
+			BrowserFingerprint: e.BrowserFingerprint,
+			RiskScore:          e.RiskScore,
+		})
+	}
+
+	return sessions, nil
+}
+
+type inviteLinkParams struct {
+	Email         string `json:"email"`
+	PersonalEmail string `json:"personal_email,omitempty"`
+}
+
+// GenerateInviteLink returns the link inviting the user with the email to set
+// their password.
+func (s *UsersService) GenerateInviteLink(ctx context.Context, email string) (string, error) {
+	u := "/api/1/invites/get_invite_link"
+
+	req, err := s.client.NewRequest("POST", u, inviteLinkParams{Email: email})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return "", err
+	}
+
+	var links []string
+	if _, err := s.client.Do(ctx, req, &links); err != nil {
+		return "", err
+	}
+
+	if len(links) == 0 {
+		return "", ErrNotFound
+	}
+
+	return links[0], nil
+}
+
+// SendInviteLink emails the invite link to the user with the email, or to each of
+// the personalEmails instead when there are some, e.g. before the corporate
+// mailbox of a new hire exists. OneLogin takes a single personal email per
+// request, so one is sent for each of them, stopping at the first failure.
+func (s *UsersService) SendInviteLink(ctx context.Context, email string, personalEmails []string) error {
+	if len(personalEmails) == 0 {
+		return s.sendInviteLink(ctx, email, "")
+	}
+
+	for _, personalEmail := range personalEmails {
+		if err := s.sendInviteLink(ctx, email, personalEmail); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UsersService) sendInviteLink(ctx context.Context, email, personalEmail string) error {
+	u := "/api/1/invites/send_invite_link"
+
+	req, err := s.client.NewRequest("POST", u, inviteLinkParams{
+		Email:         email,
+		PersonalEmail: personalEmail,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// ErrUserActivated is returned by SendEmailVerification when the user has already
+// activated their account, verifying their email.
+var ErrUserActivated = errors.New("user already activated")
+
+// UserStatusError is returned by SendEmailVerification when the user, though not
+// activated, is in a status where they can't be sent an invite link.
+type UserStatusError struct {
+	Status int64
+}
+
+func (e *UserStatusError) Error() string {
+	return fmt.Sprintf("user is %s, not unactivated", userStatusName(e.Status))
+}
+
+// SendEmailVerification asks a user who hasn't activated their account yet to
+// verify their email. OneLogin has no dedicated endpoint: the email address is
+// verified by the invite link, sent by SendInviteLink, the user follows to set
+// their password. ErrUserActivated is returned when the user is already active,
+// and a *UserStatusError when they are neither activated nor unactivated, e.g.
+// suspended or locked.
+func (s *UsersService) SendEmailVerification(ctx context.Context, userID int64) error {
+	user, err := s.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Status == UserStatusActive || !user.ActivatedAt.IsZero() {
+		return ErrUserActivated
+	}
+	if user.Status != UserStatusUnactivated {
+		return &UserStatusError{Status: user.Status}
+	}
+
+	return s.SendInviteLink(ctx, user.Email, nil)
+}
+
+// Statuses of a user.
+const (
+	UserStatusUnactivated int64 = 0
+	UserStatusActive      int64 = 1
+	UserStatusSuspended   int64 = 2
+	UserStatusLocked      int64 = 3
+)
+
+func userStatusName(status int64) string {
+	switch status {
+	case UserStatusUnactivated:
+		return "unactivated"
+	case UserStatusActive:
+		return "active"
+	case UserStatusSuspended:
+		return "suspended"
+	case UserStatusLocked:
+		return "locked"
+	}
+	return fmt.Sprintf("in status %d", status)
+}
+
+type lockUserParams struct {
+	LockedUntil int `json:"locked_until"`
+}
+
+// Lock locks a user for the given number of minutes, or indefinitely when minutes is 0.
+func (s *UsersService) Lock(ctx context.Context, userID int64, minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("invalid lock duration of %d minutes", minutes)
+	}
+
+	u := fmt.Sprintf("/api/1/users/%v/lock_user", userID)
+
+	req, err := s.client.NewRequest("PUT", u, lockUserParams{LockedUntil: minutes})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Unlock unlocks a user by making it active again.
+func (s *UsersService) Unlock(ctx context.Context, userID int64) error {
+	_, err := s.Update(ctx, userID, &UserUpdate{Status: Int64(UserStatusActive)})
+	return err
+}
+
+// CustomAttributes returns the shortnames of the custom attributes defined on the account.
+// They are the keys of User.CustomAttributes and UserUpdate.CustomAttributes.
+func (s *UsersService) CustomAttributes(ctx context.Context) ([]string, error) {
+	u := "/api/2/users/custom_attributes"
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if _, err := s.client.Do(ctx, req, &names); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// SetGroup sets the group of a user.
+func (s *UsersService) SetGroup(ctx context.Context, userID, groupID int64) error {
+	return s.updateGroup(ctx, userID, &groupID)
+}
+
+// ClearGroup removes a user from its group.
+func (s *UsersService) ClearGroup(ctx context.Context, userID int64) error {
+	return s.updateGroup(ctx, userID, nil)
+}
+
+// updateGroup sends the group_id explicitly, as null when groupID is nil since
+// omitting it would leave the group unchanged.
+func (s *UsersService) updateGroup(ctx context.Context, userID int64, groupID *int64) error {
+	u := fmt.Sprintf("/api/2/users/%v", userID)
+
+	body := map[string]*int64{
+		"group_id": groupID,
+	}
+
+	req, err := s.client.NewRequest("PUT", u, body)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// maxConcurrentRoleFetches bounds the concurrent requests made by GetRoles.
+const maxConcurrentRoleFetches = 4
+
+// GetRoleIDs returns the ids of the roles assigned to a user.
+func (s *UsersService) GetRoleIDs(ctx context.Context, userID int64) ([]int64, error) {
+	u := fmt.Sprintf("/api/2/users/%v/roles", userID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	if _, err := s.client.Do(ctx, req, &ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetRoles returns the roles assigned to a user.
+// The roles are fetched concurrently, a few at a time.
+func (s *UsersService) GetRoles(ctx context.Context, userID int64) ([]*Role, error) {
+	ids, err := s.GetRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*Role, len(ids))
+	err = parallel(ctx, len(ids), maxConcurrentRoleFetches, func(ctx context.Context, i int) error {
+		var err error
+		roles[i], err = s.client.Roles.Get(ctx, ids[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// AmbiguousMatchError is returned by GetByEmail when several users have the email.
+type AmbiguousMatchError struct {
+	Email string
+	Users []*User
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	return fmt.Sprintf("%d users match the email %q", len(e.Users), e.Email)
+}
+
+// GetByEmail returns the OneLogin user with the email.
+// ErrNotFound is returned when no user has the email, and an *AmbiguousMatchError
+// carrying the matching users when several of them do.
+func (s *UsersService) GetByEmail(ctx context.Context, email string) (*User, error) {
+	users, err := s.List(ctx, &UserListOptions{Email: email})
+	if err != nil {
+		return nil, err
+	}
+
+	// The filter isn't necessarily an exact match.
+	var matches []*User
+	for _, u := range users {
+		if strings.EqualFold(u.Email, email) {
+			matches = append(matches, u)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, &AmbiguousMatchError{Email: email, Users: matches}
+	}
+}
+
+// maxConcurrentUserLookups bounds the concurrent requests made by ListByEmails.
+const maxConcurrentUserLookups = 4
+
+// ListByEmails returns the OneLogin users with the emails, by email.
+// The users API filters on a single email at a time, so the emails are looked up
+// concurrently, a few at a time.
+// The emails without a user are missing from the map. An email matching several
+// users fails the whole lookup with an *AmbiguousMatchError.
+func (s *UsersService) ListByEmails(ctx context.Context, emails []string) (map[string]*User, error) {
+	var unique []string
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		if !seen[email] {
+			seen[email] = true
+			unique = append(unique, email)
+		}
+	}
+
+	var mu sync.Mutex
+	users := make(map[string]*User, len(unique))
+	err := parallel(ctx, len(unique), maxConcurrentUserLookups, func(ctx context.Context, i int) error {
+		user, err := s.GetByEmail(ctx, unique[i])
+		if err == ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		users[unique[i]] = user
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
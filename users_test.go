@@ -0,0 +1,145 @@
+package onelogin_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/drewsonne/onelogin"
+	"github.com/drewsonne/onelogin/onelogintest"
+)
+
+func TestUpdateUserSendsOnlySetFields(t *testing.T) {
+	tests := []struct {
+		name string
+		user *onelogin.UserUpdate
+		want string
+	}{
+		{
+			name: "firstname",
+			user: &onelogin.UserUpdate{FirstName: onelogin.String("Ada")},
+			want: `{"firstname":"Ada"}`,
+		},
+		{
+			name: "cleared title",
+			user: &onelogin.UserUpdate{Title: onelogin.String("")},
+			want: `{"title":""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			s.HandleJSON("PUT", "/api/2/users/1", http.StatusOK, map[string]interface{}{"id": 1})
+
+			c := s.Client()
+			if _, err := c.Users.Update(context.Background(), 1, tt.user); err != nil {
+				t.Fatal(err)
+			}
+
+			var body []byte
+			for _, r := range s.Requests() {
+				if r.Method == "PUT" && r.Path == "/api/2/users/1" {
+					body = bytes.TrimSpace(r.Body)
+				}
+			}
+			if string(body) != tt.want {
+				t.Errorf("got body %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendInviteLinkToPersonalEmails(t *testing.T) {
+	s := onelogintest.NewServer()
+	defer s.Close()
+	s.HandleJSON("POST", "/api/1/invites/send_invite_link", http.StatusOK, map[string]interface{}{
+		"status": map[string]interface{}{"error": false, "code": 200, "type": "success", "message": "Success"},
+	})
+
+	c := s.Client()
+	err := c.Users.SendInviteLink(context.Background(), "ada@example.com", []string{"ada@home.example", "ada@other.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bodies []string
+	for _, r := range s.Requests() {
+		if r.Path == "/api/1/invites/send_invite_link" {
+			bodies = append(bodies, string(bytes.TrimSpace(r.Body)))
+		}
+	}
+	want := []string{
+		`{"email":"ada@example.com","personal_email":"ada@home.example"}`,
+		`{"email":"ada@example.com","personal_email":"ada@other.example"}`,
+	}
+	if strings.Join(bodies, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got bodies %v, want %v", bodies, want)
+	}
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    map[string]interface{}
+		wantErr string
+		sent    bool
+	}{
+		{
+			name: "unactivated",
+			user: map[string]interface{}{"id": 1, "email": "ada@example.com", "status": onelogin.UserStatusUnactivated},
+			sent: true,
+		},
+		{
+			name:    "active",
+			user:    map[string]interface{}{"id": 1, "email": "ada@example.com", "status": onelogin.UserStatusActive},
+			wantErr: onelogin.ErrUserActivated.Error(),
+		},
+		{
+			name: "activated then locked",
+			user: map[string]interface{}{
+				"id": 1, "email": "ada@example.com", "status": onelogin.UserStatusLocked,
+				"activated_at": "2020-01-02T03:04:05Z",
+			},
+			wantErr: onelogin.ErrUserActivated.Error(),
+		},
+		{
+			name:    "suspended",
+			user:    map[string]interface{}{"id": 1, "email": "ada@example.com", "status": onelogin.UserStatusSuspended},
+			wantErr: "user is suspended, not unactivated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := onelogintest.NewServer()
+			defer s.Close()
+			s.HandleJSON("GET", "/api/2/users/1", http.StatusOK, tt.user)
+			s.HandleJSON("POST", "/api/1/invites/send_invite_link", http.StatusOK, map[string]interface{}{
+				"status": map[string]interface{}{"error": false, "code": 200, "type": "success", "message": "Success"},
+			})
+
+			c := s.Client()
+			err := c.Users.SendEmailVerification(context.Background(), 1)
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("got error %v, want %s", err, tt.wantErr)
+			}
+
+			var sent bool
+			for _, r := range s.Requests() {
+				if r.Path == "/api/1/invites/send_invite_link" {
+					sent = true
+				}
+			}
+			if sent != tt.sent {
+				t.Errorf("got invite link sent %t, want %t", sent, tt.sent)
+			}
+		})
+	}
+}